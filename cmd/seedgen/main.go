@@ -0,0 +1,60 @@
+// Command seedgen generates Go structs and accessors from a domain in JSON.
+//
+// It is designed to be used with go generate:
+//
+//	//go:generate go run github.com/xiegeo/seed/cmd/seedgen -in domain.json -out domain_gen.go
+//
+// The package name defaults to $GOPACKAGE, as set by go generate.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/gogen"
+)
+
+func main() {
+	in := flag.String("in", "", "domain definition in JSON, required")
+	out := flag.String("out", "", "output Go file, default to stdout")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package name of the output")
+	flag.Parse()
+	err := run(*in, *out, *pkg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "seedgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, pkg string) error {
+	if in == "" {
+		return fmt.Errorf("flag -in is required")
+	}
+	data, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+	var d seed.Domain
+	err = json.Unmarshal(data, &d)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", in, err)
+	}
+	err = d.Validate()
+	if err != nil {
+		return fmt.Errorf("validate %s: %w", in, err)
+	}
+	var buf bytes.Buffer
+	err = gogen.Generate(&buf, &d, gogen.Options{PackageName: pkg})
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return os.WriteFile(out, buf.Bytes(), 0o644) //nolint:gosec // generated source code is not secret
+}
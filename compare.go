@@ -0,0 +1,109 @@
+package seed
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// CompareValues compares two values of orderable field types,
+// and returns -1, 0 or +1 if a is less than, equal to, or greater than b.
+// Integers of int, int64 and *big.Int can be compared with each other, and with reals of float64
+// and decimal.Decimal if they convert exactly, see toReal. Other values must be of the same type.
+func CompareValues(a, b any) (int, error) {
+	if ai, ok := toBigInt(a); ok {
+		if bi, ok := toBigInt(b); ok {
+			return ai.Cmp(bi), nil
+		}
+	}
+	if bt, ok := toReal(b, a); ok {
+		b = bt
+	} else if at, ok := toReal(a, b); ok {
+		a = at
+	}
+	switch at := a.(type) {
+	case string:
+		if bt, ok := b.(string); ok {
+			return strings.Compare(at, bt), nil
+		}
+	case []byte:
+		if bt, ok := b.([]byte); ok {
+			return bytes.Compare(at, bt), nil
+		}
+	case bool:
+		if bt, ok := b.(bool); ok {
+			return compareBool(at, bt), nil
+		}
+	case time.Time:
+		if bt, ok := b.(time.Time); ok {
+			return compareOrdered(at.Before(bt), at.After(bt)), nil
+		}
+	case float64:
+		if bt, ok := b.(float64); ok {
+			return compareOrdered(at < bt, at > bt), nil
+		}
+	case decimal.Decimal:
+		if bt, ok := b.(decimal.Decimal); ok {
+			return at.Cmp(bt), nil
+		}
+	}
+	return 0, seederrors.NewSystemError("comparison between %T and %T not implemented", a, b)
+}
+
+func toBigInt(v any) (*big.Int, bool) {
+	switch vt := v.(type) {
+	case int:
+		return big.NewInt(int64(vt)), true
+	case int64:
+		return big.NewInt(vt), true
+	case *big.Int:
+		return vt, vt != nil
+	}
+	return nil, false
+}
+
+// toReal converts v to the type of like, float64 or decimal.Decimal, if v is an integer or a float64
+// that converts exactly. Floats convert to decimals by their shortest representation, such as 0.1.
+func toReal(v, like any) (any, bool) {
+	switch like.(type) {
+	case float64:
+		if f, ok := v.(float64); ok {
+			return f, true
+		}
+		if i, ok := toBigInt(v); ok {
+			f, accuracy := new(big.Float).SetInt(i).Float64()
+			return f, accuracy == big.Exact
+		}
+	case decimal.Decimal:
+		switch vt := v.(type) {
+		case decimal.Decimal:
+			return vt, true
+		case float64:
+			return decimal.NewFromFloat(vt), !math.IsInf(vt, 0) && !math.IsNaN(vt)
+		}
+		if i, ok := toBigInt(v); ok {
+			return decimal.NewFromBigInt(i, 0), true
+		}
+	}
+	return nil, false
+}
+
+func compareBool(a, b bool) int {
+	return compareOrdered(!a && b, a && !b)
+}
+
+func compareOrdered(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
@@ -43,6 +43,11 @@ func MakeDirectedCondition(op Op, operands ...any) (Condition, error) {
 	if !op.IsDirectional() {
 		return Condition{}, seederrors.NewSystemError("%v is not directional, MakeDirectedCondition should only be used for directional operations", op)
 	}
+	return makeCondition(op, operands...), nil
+}
+
+// makeCondition is MakeDirectedCondition without checking op.
+func makeCondition(op Op, operands ...any) Condition {
 	root := Condition{
 		Op: op,
 	}
@@ -83,7 +88,7 @@ func MakeDirectedCondition(op Op, operands ...any) (Condition, error) {
 	// operands of the same type were appended in reverse order
 	reverseSlice(root.Children)
 	reverseSlice(root.FieldPaths)
-	return root, nil
+	return root
 }
 
 func reverseSlice[T any](s []T) {
@@ -147,7 +152,8 @@ const (
 	//
 	// Implantation wise, `And` can be check by returning false on first false operand encountered,
 	// true on exhaustion (unless null is also encountered).
-	// `Or` can be check by returning true on first true operand encountered, false on exhaustion.
+	// `Or` can be check by returning true on first true operand encountered, false on exhaustion
+	// (unless null is also encountered).
 	And  // all operands are true, if |E| = 0, always true
 	Nand // if |E| = 0, always false
 	Or   // one or more operands is true, if |E| = 0, always false
@@ -157,7 +163,8 @@ const (
 
 	// A nil Literal can't be used, so use *Null for comparison against nil values.
 	// When other operators encounter nil values, it is viewed as unknown.
-	// ie: Eq(nil, nil) = nil; And(true, nil) = true; And(false, nil) = nil.
+	// ie: Eq(nil, nil) = nil; And(true, nil) = nil; And(false, nil) = false;
+	// Or(true, nil) = true; Or(false, nil) = nil. This is the three-valued logic of SQL.
 	AndIsNull  // true iff all values are nil.    If |E| = 0, always true.  Inverse of OrNotNull
 	OrIsNull   // true iff any values are nil.    If |E| = 0, always false. Inverse of AndNotNull
 	AndNotNull // true iff no  values are nil.    If |E| = 0, always true.  Inverse of OrIsNull
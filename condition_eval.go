@@ -0,0 +1,360 @@
+package seed
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// Truth is the result of a condition in three-valued logic, where null values are unknown.
+type Truth int8
+
+const (
+	Unknown Truth = iota // the zero value, result depends on null values.
+	False
+	True
+)
+
+var _truthStringer = []string{"Unknown", "False", "True"}
+
+func (t Truth) String() string {
+	if t < Unknown || t > True {
+		return fmt.Sprintf("Truth(%d) out of range[%d,%d]", t, Unknown, True)
+	}
+	return _truthStringer[t]
+}
+
+// TruthOf returns True or False from b.
+func TruthOf(b bool) Truth {
+	if b {
+		return True
+	}
+	return False
+}
+
+// Not returns the inverse of t, Unknown stays Unknown.
+func (t Truth) Not() Truth {
+	switch t {
+	case True:
+		return False
+	case False:
+		return True
+	}
+	return Unknown
+}
+
+// Value returns t as a bool, or nil if Unknown.
+func (t Truth) Value() any {
+	switch t {
+	case True:
+		return true
+	case False:
+		return false
+	}
+	return nil
+}
+
+// PathResolver returns the value found by walking path from row.
+type PathResolver func(row any, path Path) (any, error)
+
+// ResolvePath is the default PathResolver. It walks through nested values of combinations
+// and references in the plain form returned by ObjectValue.Map, as well as *ObjectValue and
+// map[string]any. Missing values are null. Walking through a list continues on each item,
+// and returns the results as a list.
+func ResolvePath(row any, path Path) (any, error) {
+	v := derefValue(row)
+	for i, name := range path {
+		switch vt := v.(type) {
+		case nil:
+			return nil, nil
+		case map[CodeName]any:
+			v = derefValue(vt[name])
+		case map[string]any:
+			v = derefValue(vt[string(name)])
+		case ObjectValue:
+			v = derefValue(vt.Fields[name].plain())
+		case ReferenceValue:
+			v = derefValue(vt.Fields[name].plain())
+		default:
+			rv := reflect.ValueOf(v)
+			if rv.Kind() != reflect.Slice {
+				return nil, seederrors.NewValueError(seederrors.ValueType, v, pathStrings(path[:i+1])...)
+			}
+			items := make([]any, rv.Len())
+			for j := range items {
+				item, err := ResolvePath(rv.Index(j).Interface(), path[i:])
+				if err != nil {
+					return nil, err
+				}
+				items[j] = item
+			}
+			return items, nil
+		}
+	}
+	return v, nil
+}
+
+func pathStrings(path Path) []string {
+	out := make([]string, len(path))
+	for i, name := range path {
+		out[i] = string(name)
+	}
+	return out
+}
+
+// Evaluate returns the truth of c for a row of values, such as an object value.
+// Field paths are resolved by resolver, or ResolvePath if resolver is nil.
+//
+// Operands are evaluated in the order of Children, FieldPaths and Literal, with PushUp children
+// expanded in place, as documented by Op. Null values are unknown, and give Unknown unless the
+// result is decided by other operands.
+func (c Condition) Evaluate(row any, resolver PathResolver) (Truth, error) {
+	if c.Op == PushUp {
+		return Unknown, seederrors.NewSystemError("PushUp can not be evaluated as a condition")
+	}
+	if resolver == nil {
+		resolver = ResolvePath
+	}
+	operands, err := c.evaluateOperands(row, resolver)
+	if err != nil {
+		return Unknown, err
+	}
+	op := c.Op
+	inverse := false
+	if op > OpMax {
+		return Unknown, seederrors.NewSystemError("%v is not an operator", op)
+	}
+	if op.isInverse() {
+		op, inverse = op.Inverse(), true
+	}
+	var t Truth
+	switch op { //nolint:exhaustive // inverses are mapped above
+	case And:
+		t, err = evaluateAnd(operands)
+	case Or:
+		t, err = evaluateOr(operands)
+	case Eq:
+		t = evaluateEq(operands)
+	case AndIsNull, AndNotNull, OrIsNull, OrNotNull:
+		t = evaluateNull(op, operands)
+	case In:
+		t = evaluateIn(operands)
+	default:
+		t, err = evaluateDirectional(op, operands)
+	}
+	if err != nil {
+		return Unknown, err
+	}
+	if inverse {
+		return t.Not(), nil
+	}
+	return t, nil
+}
+
+// isInverse returns true for operators that are evaluated as the inverse of an other operator.
+func (op Op) isInverse() bool {
+	switch op { //nolint:exhaustive // only listed operators are inverses
+	case Nand, Nor, Neq, NotIn, Nlt, Nlte, Ngt, Ngte:
+		return true
+	}
+	return false
+}
+
+func (c Condition) evaluateOperands(row any, resolver PathResolver) ([]any, error) {
+	var operands []any
+	var err error
+	c.ForEach(func(child Condition) {
+		if err != nil {
+			return
+		}
+		var t Truth
+		t, err = child.Evaluate(row, resolver)
+		operands = append(operands, t.Value())
+	}, func(path []CodeName) {
+		if err != nil {
+			return
+		}
+		var v any
+		v, err = resolver(row, path)
+		operands = append(operands, normalizeOperand(v))
+	}, func(literal any) {
+		operands = append(operands, normalizeOperand(literal))
+	})
+	return operands, err
+}
+
+// normalizeOperand removes pointers and converts CodeName to string, so that values from fields
+// and literals can be compared.
+func normalizeOperand(v any) any {
+	v = derefValue(v)
+	if cn, ok := v.(CodeName); ok {
+		return string(cn)
+	}
+	return v
+}
+
+func toTruth(op Op, v any) (Truth, error) {
+	switch vt := v.(type) {
+	case nil:
+		return Unknown, nil
+	case bool:
+		return TruthOf(vt), nil
+	}
+	return Unknown, seederrors.NewValueError(seederrors.ValueType, v, op.String())
+}
+
+func evaluateAnd(operands []any) (Truth, error) {
+	out := True
+	for _, v := range operands {
+		t, err := toTruth(And, v)
+		if err != nil {
+			return Unknown, err
+		}
+		switch t {
+		case False:
+			return False, nil
+		case Unknown:
+			out = Unknown
+		}
+	}
+	return out, nil
+}
+
+func evaluateOr(operands []any) (Truth, error) {
+	out := False
+	for _, v := range operands {
+		t, err := toTruth(Or, v)
+		if err != nil {
+			return Unknown, err
+		}
+		switch t {
+		case True:
+			return True, nil
+		case Unknown:
+			out = Unknown
+		}
+	}
+	return out, nil
+}
+
+func evaluateEq(operands []any) Truth {
+	if len(operands) < 2 {
+		return True
+	}
+	out := True
+	var first any
+	for _, v := range operands {
+		switch {
+		case v == nil:
+			out = Unknown
+		case first == nil:
+			first = v
+		case !equalValues(first, v):
+			return False
+		}
+	}
+	return out
+}
+
+func evaluateNull(op Op, operands []any) Truth {
+	isNull := op == AndIsNull || op == OrIsNull
+	isAnd := op == AndIsNull || op == AndNotNull
+	for _, v := range operands {
+		if (v == nil) != isNull {
+			if isAnd {
+				return False
+			}
+		} else if !isAnd {
+			return True
+		}
+	}
+	return TruthOf(isAnd)
+}
+
+func evaluateIn(operands []any) Truth {
+	var intersect []any
+	started, unknown := false, false
+	for _, v := range operands {
+		if v == nil {
+			unknown = true
+			continue
+		}
+		if started {
+			intersect = intersectValues(intersect, toSet(v))
+		} else {
+			intersect, started = toSet(v), true
+		}
+		if len(intersect) == 0 {
+			return False // can not be made true by unknown operands
+		}
+	}
+	if unknown {
+		return Unknown
+	}
+	return True
+}
+
+// toSet promotes a value to a set, and removes null items.
+func toSet(v any) []any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return []any{v}
+	}
+	if _, ok := v.([]byte); ok {
+		return []any{v}
+	}
+	out := make([]any, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		item := normalizeOperand(rv.Index(i).Interface())
+		if item != nil {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func intersectValues(a, b []any) []any {
+	out := make([]any, 0, len(a))
+	for _, av := range a {
+		for _, bv := range b {
+			if equalValues(av, bv) {
+				out = append(out, av)
+				break
+			}
+		}
+	}
+	return out
+}
+
+func evaluateDirectional(op Op, operands []any) (Truth, error) {
+	out := True
+	for i := 1; i < len(operands); i++ {
+		a, b := operands[i-1], operands[i]
+		if a == nil || b == nil {
+			out = Unknown
+			continue
+		}
+		cmp, err := CompareValues(a, b)
+		if err != nil {
+			return Unknown, err
+		}
+		var ok bool
+		switch op { //nolint:exhaustive // only directional operators are passed in
+		case Lt:
+			ok = cmp < 0
+		case Lte:
+			ok = cmp <= 0
+		case Gt:
+			ok = cmp > 0
+		case Gte:
+			ok = cmp >= 0
+		default:
+			return Unknown, seederrors.NewSystemError("%v is not directional", op)
+		}
+		if !ok {
+			return False, nil
+		}
+	}
+	return out, nil
+}
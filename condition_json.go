@@ -0,0 +1,214 @@
+package seed
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// In JSON, a condition is an object of Op, Children, FieldPaths and Literal, where Op is named as
+// printed by Op.String and paths are lists of names. The zero condition is null.
+//
+// Literals are typed as {"Type":..., "Value":...}, with these types:
+//
+//	String     JSON string
+//	Boolean    JSON boolean
+//	Integer    JSON number without fraction, decoded as int64, or *big.Int if too large for int64
+//	Real       JSON number, decoded as float64
+//	Decimal    JSON string, such as "1.50", decoded as decimal.Decimal
+//	TimeStamp  JSON string in RFC 3339, decoded as time.Time
+//	Binary     JSON string in standard base64, decoded as []byte
+//	List       JSON array of typed literals, decoded as []any
+//	Quantity   JSON object of a typed literal Value and a Unit, decoded as Quantity
+//
+// For example: {"Op":"Lt","FieldPaths":[["event","start_time"]],"Literal":{"Type":"TimeStamp","Value":"2023-01-01T00:00:00Z"}}
+
+func (op Op) MarshalText() ([]byte, error) {
+	return marshalEnum("Op", _opString[:], op)
+}
+
+func (op *Op) UnmarshalText(text []byte) error {
+	return unmarshalEnum("Op", _opString[:], op, text)
+}
+
+type conditionJSON struct {
+	Op         Op
+	Children   []Condition  `json:",omitempty"`
+	FieldPaths []Path       `json:",omitempty"`
+	Literal    *literalJSON `json:",omitempty"`
+}
+
+type literalJSON struct {
+	Type  string
+	Value json.RawMessage
+}
+
+const (
+	literalString    = "String"
+	literalBoolean   = "Boolean"
+	literalInteger   = "Integer"
+	literalReal      = "Real"
+	literalDecimal   = "Decimal"
+	literalTimeStamp = "TimeStamp"
+	literalBinary    = "Binary"
+	literalList      = "List"
+	literalQuantity  = "Quantity"
+)
+
+type quantityJSON struct {
+	Value literalJSON
+	Unit  *Unit
+}
+
+func (c Condition) isZero() bool {
+	return c.Op == PushUp && len(c.Children) == 0 && len(c.FieldPaths) == 0 && c.Literal == nil
+}
+
+// MarshalJSON encodes c with operators by name and typed literals, the zero condition is null.
+func (c Condition) MarshalJSON() ([]byte, error) {
+	if c.isZero() {
+		return []byte("null"), nil
+	}
+	out := conditionJSON{
+		Op:         c.Op,
+		Children:   c.Children,
+		FieldPaths: c.FieldPaths,
+	}
+	if c.Literal != nil {
+		lit, err := marshalLiteral(c.Literal)
+		if err != nil {
+			return nil, err
+		}
+		out.Literal = &lit
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes c, unknown operators and literal types are rejected.
+func (c *Condition) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*c = Condition{}
+		return nil
+	}
+	var in conditionJSON
+	err := json.Unmarshal(data, &in)
+	if err != nil {
+		return err
+	}
+	*c = Condition{
+		Op:         in.Op,
+		Children:   in.Children,
+		FieldPaths: in.FieldPaths,
+	}
+	if in.Literal != nil {
+		c.Literal, err = unmarshalLiteral(*in.Literal)
+	}
+	return err
+}
+
+//nolint:cyclop // one case per literal type
+func marshalLiteral(v any) (literalJSON, error) {
+	var t string
+	var value any
+	switch vt := v.(type) {
+	case bool:
+		t, value = literalBoolean, vt
+	case string:
+		t, value = literalString, vt
+	case CodeName:
+		t, value = literalString, vt
+	case int:
+		t, value = literalInteger, vt
+	case int64:
+		t, value = literalInteger, vt
+	case *big.Int:
+		t, value = literalInteger, vt
+	case float64:
+		if math.IsNaN(vt) || math.IsInf(vt, 0) {
+			return literalJSON{}, seederrors.NewSystemError("literal %v can not be encoded", vt)
+		}
+		t, value = literalReal, vt
+	case decimal.Decimal:
+		t, value = literalDecimal, decimalString(vt)
+	case time.Time:
+		t, value = literalTimeStamp, vt
+	case []byte:
+		t, value = literalBinary, vt
+	case Quantity:
+		lit, err := marshalLiteral(vt.Value)
+		if err != nil {
+			return literalJSON{}, err
+		}
+		t, value = literalQuantity, quantityJSON{Value: lit, Unit: vt.Unit}
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice {
+			return literalJSON{}, seederrors.NewSystemError("literal of %T can not be encoded", v)
+		}
+		items := make([]literalJSON, rv.Len())
+		for i := range items {
+			var err error
+			items[i], err = marshalLiteral(rv.Index(i).Interface())
+			if err != nil {
+				return literalJSON{}, err
+			}
+		}
+		t, value = literalList, items
+	}
+	data, err := json.Marshal(value)
+	return literalJSON{Type: t, Value: data}, err
+}
+
+func unmarshalLiteral(lit literalJSON) (any, error) {
+	if len(lit.Value) == 0 || bytes.Equal(lit.Value, []byte("null")) {
+		return nil, seederrors.NewValueRequiredError("Literal.Value")
+	}
+	switch lit.Type {
+	case literalString:
+		return unmarshalTo[string](lit.Value)
+	case literalBoolean:
+		return unmarshalTo[bool](lit.Value)
+	case literalInteger:
+		return unmarshalValue(Integer, nil, lit.Value)
+	case literalReal:
+		return unmarshalTo[float64](lit.Value)
+	case literalDecimal:
+		s, err := unmarshalTo[string](lit.Value)
+		if err != nil {
+			return nil, err
+		}
+		return decimal.NewFromString(s)
+	case literalTimeStamp:
+		return unmarshalTo[time.Time](lit.Value)
+	case literalBinary:
+		return unmarshalTo[[]byte](lit.Value)
+	case literalList:
+		items, err := unmarshalTo[[]literalJSON](lit.Value)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]any, len(items))
+		for i, item := range items {
+			out[i], err = unmarshalLiteral(item)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case literalQuantity:
+		q, err := unmarshalTo[quantityJSON](lit.Value)
+		if err != nil {
+			return nil, err
+		}
+		v, err := unmarshalLiteral(q.Value)
+		return Quantity{Value: v, Unit: q.Unit}, err
+	}
+	return nil, seederrors.NewUnknownNameError("literal type", lit.Type)
+}
@@ -0,0 +1,78 @@
+package seed_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
+
+	. "github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+func TestQueryJSON(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	q := Query{
+		ObjectName: ObjectNamePath{Domain: "test", Object: "event"},
+		Fields:     NameTree{"title": nil, "location": NameTree{"name": nil}},
+		Condition: Condition{Op: And, Children: []Condition{
+			must.V(MakeDirectedCondition(Lt, Path{"start_time"}, time.Date(2023, 1, 2, 3, 4, 5, 6, time.UTC))),
+			{Op: Eq, FieldPaths: []Path{{"count"}}, Literal: huge},
+			{Op: Eq, FieldPaths: []Path{{"price"}}, Literal: must.V(decimal.NewFromString("1.50"))},
+			{Op: Neq, FieldPaths: []Path{{"data"}}, Literal: []byte{1, 2, 3}},
+			{Op: In, FieldPaths: []Path{{"status"}}, Literal: []any{"draft", int64(3), 1.5, true}},
+		}},
+		Order:  []PartialOrder{{FieldPath: []CodeName{"start_time"}, Inverse: true}},
+		Offset: 10,
+		Limit:  20,
+	}
+	data, err := json.Marshal(q)
+	require.NoError(t, err)
+
+	var decoded Query
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, q, decoded)
+	data2, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(data2))
+
+	data, err = json.Marshal(Query{ObjectName: ObjectNamePath{Domain: "test", Object: "event"}, Count: true})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ObjectName":{"Domain":"test","Object":"event"},"Condition":null,"Count":true,"Having":null}`, string(data))
+}
+
+func TestConditionJSON(t *testing.T) {
+	c := must.V(MakeDirectedCondition(Lte, Path{"event", "start_time"}, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)))
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"Op":"Lte",
+		"FieldPaths":[["event","start_time"]],
+		"Literal":{"Type":"TimeStamp","Value":"2023-01-01T00:00:00Z"}
+	}`, string(data))
+
+	var decoded Condition
+	require.NoError(t, json.Unmarshal([]byte(`{"Op":"Eq","FieldPaths":[["a"]],"Literal":{"Type":"Integer","Value":12}}`), &decoded))
+	assert.Equal(t, Condition{Op: Eq, FieldPaths: []Path{{"a"}}, Literal: int64(12)}, decoded)
+
+	err = json.Unmarshal([]byte(`{"Op":"Equals","FieldPaths":[["a"]]}`), &decoded)
+	assert.ErrorAs(t, err, &seederrors.UnknownNameError{}, "unknown operator")
+	err = json.Unmarshal([]byte(`{"Op":"eq","FieldPaths":[["a"]]}`), &decoded)
+	assert.ErrorAs(t, err, &seederrors.UnknownNameError{}, "operator names are case sensitive")
+	err = json.Unmarshal([]byte(`{"Op":"And","Children":[{"Op":"Is","FieldPaths":[["a"]]}]}`), &decoded)
+	assert.ErrorAs(t, err, &seederrors.UnknownNameError{}, "unknown operator in children")
+	err = json.Unmarshal([]byte(`{"Op":"Eq","FieldPaths":[["a"]],"Literal":{"Type":"Date","Value":"2023-01-01"}}`), &decoded)
+	assert.ErrorAs(t, err, &seederrors.UnknownNameError{}, "unknown literal type")
+	err = json.Unmarshal([]byte(`{"Op":"Eq","FieldPaths":[["a"]],"Literal":{"Type":"Integer"}}`), &decoded)
+	assert.ErrorAs(t, err, &seederrors.ValueRequiredError{}, "literal without value")
+	err = json.Unmarshal([]byte(`{"Op":"Eq","FieldPaths":[["a"]],"Literal":{"Type":"Integer","Value":1.5}}`), &decoded)
+	assert.Error(t, err, "integer with fraction")
+
+	_, err = json.Marshal(Condition{Op: Eq, FieldPaths: []Path{{"a"}}, Literal: struct{}{}})
+	assert.Error(t, err, "literal type not supported")
+}
@@ -0,0 +1,424 @@
+package seed
+
+import "reflect"
+
+// operandKind orders operands as directional operators apply them.
+type operandKind uint8
+
+const (
+	childOperand operandKind = iota
+	pathOperand
+	literalOperand
+)
+
+type operand struct {
+	kind       operandKind
+	child      Condition
+	path       Path
+	literal    any
+	simplified bool // child is already simplified
+}
+
+// operands returns the operands of c in order, with PushUp children expanded in place.
+func (c Condition) operands() []operand {
+	var out []operand
+	c.ForEach(func(child Condition) {
+		out = append(out, operand{kind: childOperand, child: child})
+	}, func(path []CodeName) {
+		out = append(out, operand{kind: pathOperand, path: path})
+	}, func(literal any) {
+		out = append(out, operand{kind: literalOperand, literal: literal})
+	})
+	return out
+}
+
+// conditionOf builds a condition from operands in the order of Children, FieldPaths and Literal,
+// with at most one literal.
+func conditionOf(op Op, ops []operand) Condition {
+	out := Condition{Op: op}
+	for _, o := range ops {
+		switch o.kind {
+		case childOperand:
+			out.Children = append(out.Children, o.child)
+		case pathOperand:
+			out.FieldPaths = append(out.FieldPaths, o.path)
+		case literalOperand:
+			out.Literal = o.literal
+		}
+	}
+	return out
+}
+
+func (o operand) pushUp() Condition {
+	switch o.kind {
+	case childOperand:
+		return Condition{Op: PushUp, Children: []Condition{o.child}}
+	case pathOperand:
+		return Condition{Op: PushUp, FieldPaths: []Path{o.path}}
+	}
+	return Condition{Op: PushUp, Literal: o.literal}
+}
+
+// inOrder returns true if ops can be held by a condition without PushUp children.
+func inOrder(ops []operand) bool {
+	for i := 1; i < len(ops); i++ {
+		if ops[i].kind < ops[i-1].kind || ops[i].kind == literalOperand && ops[i-1].kind == literalOperand {
+			return false
+		}
+	}
+	return true
+}
+
+// constant returns the simplest condition that is always b: And without operands for true,
+// and Or without operands for false.
+func constant(b bool) Condition {
+	if b {
+		return Condition{Op: And}
+	}
+	return Condition{Op: Or}
+}
+
+// IsConstant returns the value of c and true if c is a constant as returned by Simplify.
+func (c Condition) IsConstant() (value bool, ok bool) {
+	if len(c.Children) > 0 || len(c.FieldPaths) > 0 || c.Literal != nil {
+		return false, false
+	}
+	switch c.Op { //nolint:exhaustive // only And and Or are constants
+	case And:
+		return true, true
+	case Or:
+		return false, true
+	}
+	return false, false
+}
+
+// Simplify returns a condition that evaluates the same as c, using fewer operators:
+//   - PushUp children are removed, reordering operands or splitting directional chains when needed.
+//   - Nested And and Or are flattened.
+//   - Negations are pushed down to leaves by Op.Inverse, so And and Or are the only boolean operators left.
+//     Negated boolean fields become {field Eq false}.
+//   - Operands that are constant, including literals and conditions without enough operands, are folded.
+//     The results are And without operands for true, and Or without operands for false.
+func (c Condition) Simplify() Condition {
+	return c.simplify(false)
+}
+
+func (c Condition) simplify(negate bool) Condition {
+	op := c.Op
+	if op == PushUp || op > OpMax {
+		return c // not valid as a condition, kept for Evaluate to report
+	}
+	if negate {
+		op = op.Inverse()
+	}
+	switch op { //nolint:exhaustive // other operators are leaves
+	case And, Or:
+		return simplifyJunction(op, c.operands(), false)
+	case Nand, Nor:
+		return simplifyJunction(dual(op.Inverse()), c.operands(), true)
+	}
+	if op.isNegative() {
+		return negateSimplified(simplifyLeaf(op.Inverse(), c.operands()))
+	}
+	return simplifyLeaf(op, c.operands())
+}
+
+// isNegative returns true for leaf operators that are simplified as the inverse of an other operator.
+func (op Op) isNegative() bool {
+	switch op { //nolint:exhaustive // only listed operators are negative
+	case Neq, OrNotNull, AndNotNull, NotIn, Nlt, Nlte, Ngt, Ngte:
+		return true
+	}
+	return false
+}
+
+// simplifyJunction simplifies And or Or, negating all operands if negate is true.
+func simplifyJunction(op Op, ops []operand, negate bool) Condition {
+	out := Condition{Op: op}
+	identity := op == And // value that can be dropped
+	for _, o := range ops {
+		var child Condition
+		switch o.kind {
+		case childOperand:
+			child = o.child
+			if !o.simplified {
+				child = child.simplify(negate)
+			}
+		case pathOperand:
+			if !negate {
+				out.FieldPaths = append(out.FieldPaths, o.path)
+				continue
+			}
+			child = Condition{Op: Eq, FieldPaths: []Path{o.path}, Literal: false}
+		case literalOperand:
+			b, ok := o.literal.(bool)
+			if !ok { // not valid, kept for Evaluate to report
+				out.Children = append(out.Children, o.pushUp())
+				continue
+			}
+			child = constant(b != negate)
+		}
+		if value, ok := child.IsConstant(); ok {
+			if value == identity {
+				continue
+			}
+			return child
+		}
+		if child.Op == op && child.Literal == nil {
+			out.Children = append(out.Children, child.Children...)
+			out.FieldPaths = append(out.FieldPaths, child.FieldPaths...)
+			continue
+		}
+		out.Children = append(out.Children, child)
+	}
+	if len(out.Children) == 1 && len(out.FieldPaths) == 0 && out.Children[0].Op != PushUp {
+		return out.Children[0]
+	}
+	return out
+}
+
+// negateSimplified returns the negation of a simplified condition.
+func negateSimplified(c Condition) Condition {
+	if value, ok := c.IsConstant(); ok {
+		return constant(!value)
+	}
+	switch c.Op { //nolint:exhaustive // other operators are leaves
+	case And, Or:
+		return simplifyJunction(dual(c.Op), c.operands(), true)
+	}
+	c.Op = c.Op.Inverse()
+	return c
+}
+
+// dual swaps And and Or.
+func dual(op Op) Op {
+	if op == And {
+		return Or
+	}
+	return And
+}
+
+// simplifyLeaf simplifies a condition of a positive leaf operator.
+func simplifyLeaf(op Op, ops []operand) Condition {
+	for i, o := range ops {
+		if o.kind == childOperand {
+			ops[i].child = o.child.simplify(false)
+		}
+	}
+	if op.IsDirectional() {
+		return simplifyDirectional(op, ops)
+	}
+	ops, folded, ok := foldLiterals(op, ops)
+	if ok {
+		return folded
+	}
+	switch op { //nolint:exhaustive // only operators with a minimum number of operands
+	case Eq:
+		if len(ops) < 2 {
+			return constant(true)
+		}
+	}
+	return foldConstant(conditionOf(op, orderOperands(ops)))
+}
+
+// orderOperands returns ops in the order of children, paths, and literals, for operators that
+// do not care about the order of operands.
+func orderOperands(ops []operand) []operand {
+	out := make([]operand, 0, len(ops))
+	for kind := childOperand; kind <= literalOperand; kind++ {
+		for _, o := range ops {
+			if o.kind == kind {
+				out = append(out, o)
+			}
+		}
+	}
+	return out
+}
+
+// foldLiterals merges repeated literals of Eq, In, AndIsNull and OrIsNull. It returns the remaining
+// operands, or a constant and true if the result no longer depends on other operands.
+func foldLiterals(op Op, ops []operand) ([]operand, Condition, bool) {
+	out := make([]operand, 0, len(ops))
+	var literal *operand
+	for _, o := range ops {
+		if o.kind != literalOperand {
+			out = append(out, o)
+			continue
+		}
+		switch op { //nolint:exhaustive // other operators keep literals
+		case AndIsNull: // literals are not null
+			return nil, constant(false), true
+		case OrIsNull:
+			continue
+		case Eq:
+			if literal != nil {
+				if !equalValues(normalizeOperand(literal.literal), normalizeOperand(o.literal)) {
+					return nil, constant(false), true
+				}
+				continue
+			}
+		case In:
+			if literal != nil {
+				set := intersectValues(toSet(normalizeOperand(literal.literal)), toSet(normalizeOperand(o.literal)))
+				if len(set) == 0 {
+					return nil, constant(false), true
+				}
+				literal.literal = set
+				continue
+			}
+		}
+		literal = &operand{kind: literalOperand, literal: o.literal}
+	}
+	if literal != nil {
+		out = append(out, *literal)
+	}
+	return out, Condition{}, false
+}
+
+// foldConstant evaluates c if it does not depend on any field.
+func foldConstant(c Condition) Condition {
+	if len(c.FieldPaths) > 0 {
+		return c
+	}
+	for _, child := range c.Children {
+		if _, ok := child.IsConstant(); !ok && child.Op != PushUp {
+			return c
+		}
+		if child.Op == PushUp && (len(child.FieldPaths) > 0 || len(child.Children) > 0) {
+			return c
+		}
+	}
+	t, err := c.Evaluate(nil, nil)
+	if err != nil || t == Unknown {
+		return c
+	}
+	return constant(t == True)
+}
+
+// _opMirror swaps the order of operands of directional operators, such as {a<b} to {b>a}.
+var _opMirror = map[Op]Op{Lt: Gt, Gt: Lt, Lte: Gte, Gte: Lte}
+
+// simplifyDirectional simplifies Lt, Lte, Gt or Gte. Chains that can not be held without PushUp
+// are split into pairs, such as {a<b<c} to {(a<b)&(b<c)}, with operands of each pair swapped
+// as needed.
+func simplifyDirectional(op Op, ops []operand) Condition {
+	if len(ops) < 2 {
+		return constant(true)
+	}
+	if inOrder(ops) {
+		return foldConstant(conditionOf(op, ops))
+	}
+	pairs := make([]operand, 0, len(ops)-1)
+	for i := 1; i < len(ops); i++ {
+		a, b, pairOp := ops[i-1], ops[i], op
+		if a.kind > b.kind {
+			a, b, pairOp = b, a, _opMirror[op]
+		}
+		var pair Condition
+		if a.kind == literalOperand && b.kind == literalOperand {
+			pair = foldConstant(Condition{Op: pairOp, Children: []Condition{a.pushUp()}, Literal: b.literal})
+		} else {
+			pair = foldConstant(conditionOf(pairOp, []operand{a, b}))
+		}
+		pairs = append(pairs, operand{kind: childOperand, child: pair, simplified: true})
+	}
+	return simplifyJunction(And, pairs, false)
+}
+
+// CNF returns c simplified to the conjunctive normal form: an And of Or clauses, where the operands of
+// the clauses are leaves or boolean fields. The result can be exponentially larger than c.
+func (c Condition) CNF() Condition {
+	return normalForm(c.Simplify(), And)
+}
+
+// DNF returns c simplified to the disjunctive normal form: an Or of And clauses, where the operands of
+// the clauses are leaves or boolean fields. The result can be exponentially larger than c.
+func (c Condition) DNF() Condition {
+	return normalForm(c.Simplify(), Or)
+}
+
+func normalForm(c Condition, outer Op) Condition {
+	inner := dual(outer)
+	clauses := clausesOf(c, outer, inner)
+	ops := make([]operand, len(clauses))
+	for i, clause := range clauses {
+		ops[i] = operand{kind: childOperand, child: simplifyJunction(inner, childOperands(clause), false), simplified: true}
+	}
+	return simplifyJunction(outer, ops, false)
+}
+
+// clausesOf returns the clauses of a simplified condition in normal form, as lists of operands.
+func clausesOf(c Condition, outer, inner Op) [][]Condition {
+	if value, ok := c.IsConstant(); ok {
+		if value == (outer == And) {
+			return nil // no clauses
+		}
+		return [][]Condition{nil} // one empty clause
+	}
+	switch c.Op { //nolint:exhaustive // other operators are leaves
+	case outer:
+		var out [][]Condition
+		for _, o := range c.operands() {
+			out = append(out, o.clauses(outer, inner)...)
+		}
+		return out
+	case inner:
+		out := [][]Condition{nil}
+		for _, o := range c.operands() {
+			sub := o.clauses(outer, inner)
+			product := make([][]Condition, 0, len(out)*len(sub))
+			for _, a := range out {
+				for _, b := range sub {
+					product = append(product, appendUnique(append([]Condition(nil), a...), b...))
+				}
+			}
+			out = product
+		}
+		return out
+	}
+	return [][]Condition{{c}}
+}
+
+// clauses returns the clauses of an operand, with boolean fields and literals as leaves wrapped in inner.
+func (o operand) clauses(outer, inner Op) [][]Condition {
+	if o.kind == childOperand {
+		return clausesOf(o.child, outer, inner)
+	}
+	return [][]Condition{{o.asCondition(inner)}}
+}
+
+func childOperands(cs []Condition) []operand {
+	out := make([]operand, len(cs))
+	for i, c := range cs {
+		out[i] = operand{kind: childOperand, child: c, simplified: true}
+	}
+	return out
+}
+
+// asCondition returns a condition that evaluates the same as the operand, with boolean fields and
+// literals wrapped in op.
+func (o operand) asCondition(op Op) Condition {
+	switch o.kind {
+	case pathOperand:
+		return Condition{Op: op, FieldPaths: []Path{o.path}}
+	case literalOperand:
+		return Condition{Op: op, Literal: o.literal}
+	}
+	return o.child
+}
+
+func appendUnique(a []Condition, b ...Condition) []Condition {
+	for _, v := range b {
+		found := false
+		for _, existing := range a {
+			if reflect.DeepEqual(existing, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			a = append(a, v)
+		}
+	}
+	return a
+}
@@ -0,0 +1,141 @@
+package seed_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
+
+	. "github.com/xiegeo/seed"
+)
+
+// simplifyRows returns rows with all combinations of small, equal, and null values.
+func simplifyRows() []map[CodeName]any {
+	ints := []any{nil, int64(1), int64(2)}
+	bools := []any{nil, true, false}
+	var rows []map[CodeName]any
+	for _, a := range ints {
+		for _, b := range ints {
+			for _, p := range bools {
+				for _, q := range bools {
+					rows = append(rows, map[CodeName]any{"a": a, "b": b, "p": p, "q": q})
+				}
+			}
+		}
+	}
+	return rows
+}
+
+func simplifyConditions() []Condition {
+	a, b, p, q := NewPath("a"), NewPath("b"), NewPath("p"), NewPath("q")
+	aLtB := Condition{Op: Lt, FieldPaths: []Path{a, b}}
+	return []Condition{
+		{Op: And},
+		{Op: Nor, FieldPaths: []Path{p}, Literal: false},
+		{Op: Nand, Children: []Condition{aLtB, {Op: Or, FieldPaths: []Path{p, q}}}},
+		{Op: Nor, Children: []Condition{aLtB, {Op: Nand, FieldPaths: []Path{p, q}}}},
+		{Op: And, Children: []Condition{{Op: And, FieldPaths: []Path{p}}, {Op: Or, Literal: true}}, FieldPaths: []Path{q}},
+		{Op: Or, Children: []Condition{{Op: And, FieldPaths: []Path{p}, Literal: false}, {Op: Eq, FieldPaths: []Path{a}}}},
+		{Op: Neq, FieldPaths: []Path{a, b}},
+		{Op: Eq, Children: []Condition{{Op: Eq, Literal: 1}}, FieldPaths: []Path{a}, Literal: 1},
+		{Op: Eq, Children: []Condition{{Op: PushUp, Literal: 1}}, FieldPaths: []Path{a}, Literal: 2},
+		{Op: In, Children: []Condition{{Op: PushUp, Literal: []int{1, 2}}}, FieldPaths: []Path{a}, Literal: []int{2, 3}},
+		{Op: NotIn, FieldPaths: []Path{a, b}},
+		{Op: AndIsNull, FieldPaths: []Path{a}, Literal: 1},
+		{Op: OrNotNull, FieldPaths: []Path{a, b}},
+		{Op: AndNotNull, Children: []Condition{aLtB}, Literal: 1},
+		must.V(MakeDirectedCondition(Lt, a, 2, b)),
+		must.V(MakeDirectedCondition(Ngte, a, 1, 2, b)),
+		must.V(MakeDirectedCondition(Lte, 1, a, b)),
+		must.V(MakeDirectedCondition(Nlt, 2, a)),
+		{Op: Gt, FieldPaths: []Path{a}},
+		{Op: Nand, Children: []Condition{
+			{Op: Or, Children: []Condition{aLtB, {Op: Nor, FieldPaths: []Path{p}}}},
+			{Op: Or, Children: []Condition{{Op: OrIsNull, FieldPaths: []Path{b}}, must.V(MakeDirectedCondition(Gte, b, 1, a))}, FieldPaths: []Path{q}},
+		}},
+	}
+}
+
+func TestConditionSimplify(t *testing.T) {
+	rows := simplifyRows()
+	for i, c := range simplifyConditions() {
+		simple := c.Simplify()
+		cnf := c.CNF()
+		dnf := c.DNF()
+		assertSimplified(t, simple)
+		assertNormalForm(t, cnf, And)
+		assertNormalForm(t, dnf, Or)
+		assert.Equal(t, simple, simple.Simplify(), "simplify is idempotent %d", i)
+		for _, row := range rows {
+			msg := fmt.Sprintf("condition %d: %v row: %v", i, c, row)
+			want, err := c.Evaluate(row, nil)
+			require.NoError(t, err, msg)
+			for _, got := range []Condition{simple, cnf, dnf} {
+				gotTruth, err := got.Evaluate(row, nil)
+				require.NoError(t, err, msg)
+				require.Equal(t, want, gotTruth, "%s simplified: %v", msg, got)
+			}
+		}
+	}
+}
+
+func TestConditionSimplifyResults(t *testing.T) {
+	a, b, p := NewPath("a"), NewPath("b"), NewPath("p")
+	for _, tt := range []struct {
+		cond Condition
+		want Condition
+	}{
+		{Condition{Op: Nand}, Condition{Op: Or}},
+		{Condition{Op: Lt, FieldPaths: []Path{a}}, Condition{Op: And}},
+		{Condition{Op: Eq, Children: []Condition{{Op: PushUp, Literal: 1}}, Literal: 2}, Condition{Op: Or}},
+		{Condition{Op: Nand, FieldPaths: []Path{p}, Children: []Condition{{Op: Lt, FieldPaths: []Path{a, b}}}},
+			Condition{Op: Or, Children: []Condition{{Op: Nlt, FieldPaths: []Path{a, b}}, {Op: Eq, FieldPaths: []Path{p}, Literal: false}}}},
+		{must.V(MakeDirectedCondition(Lt, 1, a, 3)), Condition{Op: And, Children: []Condition{
+			{Op: Gt, FieldPaths: []Path{a}, Literal: 1},
+			{Op: Lt, FieldPaths: []Path{a}, Literal: 3},
+		}}},
+		{Condition{Op: And, Children: []Condition{{Op: And, FieldPaths: []Path{a}}}, FieldPaths: []Path{b}}, Condition{Op: And, FieldPaths: []Path{a, b}}},
+	} {
+		assert.Equal(t, tt.want, tt.cond.Simplify(), tt.cond)
+	}
+	v, ok := Condition{Op: And}.IsConstant()
+	assert.True(t, v && ok)
+	_, ok = Condition{Op: And, FieldPaths: []Path{a}}.IsConstant()
+	assert.False(t, ok)
+}
+
+func assertSimplified(t *testing.T, c Condition) {
+	t.Helper()
+	assert.NotContains(t, []Op{PushUp, Nand, Nor}, c.Op, c)
+	for _, child := range c.Children {
+		assertSimplified(t, child)
+	}
+}
+
+// assertNormalForm checks that c is an outer of inner clauses, allowing clauses and leaves to be collapsed.
+func assertNormalForm(t *testing.T, c Condition, outer Op) {
+	t.Helper()
+	inner := And
+	if outer == And {
+		inner = Or
+	}
+	isJunction := func(c Condition) bool { return c.Op == And || c.Op == Or }
+	assertSimplified(t, c)
+	if c.Op == inner {
+		c = Condition{Op: outer, Children: []Condition{c}}
+	}
+	if c.Op != outer {
+		return // a single leaf
+	}
+	for _, clause := range c.Children {
+		if clause.Op != inner {
+			assert.False(t, isJunction(clause), "clause %v", clause)
+			continue
+		}
+		for _, leaf := range clause.Children {
+			assert.False(t, isJunction(leaf), "leaf %v", leaf)
+		}
+	}
+}
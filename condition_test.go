@@ -1,12 +1,17 @@
 package seed_test
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
 
 	. "github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/demo/testdomain"
+	"github.com/xiegeo/seed/seederrors"
 )
 
 func TestInverse(t *testing.T) {
@@ -73,3 +78,116 @@ func TestMakeDirectedCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestConditionEvaluate(t *testing.T) {
+	row := map[CodeName]any{
+		"a":     int64(1),
+		"b":     2,
+		"c":     big.NewInt(3),
+		"null":  nil,
+		"yes":   true,
+		"no":    false,
+		"tags":  []string{"x", "y"},
+		"state": CodeName("draft"),
+		"home":  map[CodeName]any{"city": "Paris"},
+		"lines": []any{map[CodeName]any{"n": int64(1)}, map[CodeName]any{"n": int64(2)}},
+		"float": 1.0,
+		"price": decimal.RequireFromString("1.5"),
+	}
+	a, b, c, null := NewPath("a"), NewPath("b"), NewPath("c"), NewPath("null")
+	yes, no := NewPath("yes"), NewPath("no")
+	tests := []struct {
+		name string
+		cond Condition
+		want Truth
+	}{
+		{"empty And", Condition{Op: And}, True},
+		{"empty Or", Condition{Op: Or}, False},
+		{"empty Nand", Condition{Op: Nand}, False},
+		{"empty Nor", Condition{Op: Nor}, True},
+		{"And unknown", Condition{Op: And, FieldPaths: []Path{yes, null}}, Unknown},
+		{"And false wins", Condition{Op: And, FieldPaths: []Path{no, null}}, False},
+		{"Or true wins", Condition{Op: Or, FieldPaths: []Path{yes, null}}, True},
+		{"Or unknown", Condition{Op: Or, FieldPaths: []Path{no, null}}, Unknown},
+		{"And only null", Condition{Op: And, FieldPaths: []Path{null}}, Unknown},
+		{"Or only null", Condition{Op: Or, FieldPaths: []Path{null}}, Unknown},
+		{"Nand unknown", Condition{Op: Nand, FieldPaths: []Path{yes, null}}, Unknown},
+		{"Nand false wins", Condition{Op: Nand, FieldPaths: []Path{no, null}}, True},
+		{"Nor true wins", Condition{Op: Nor, FieldPaths: []Path{yes, null}}, False},
+		{"Eq one", Condition{Op: Eq, FieldPaths: []Path{a}}, True},
+		{"Eq one null", Condition{Op: Eq, FieldPaths: []Path{null}}, True},
+		{"Eq ints", Condition{Op: Eq, FieldPaths: []Path{a}, Literal: 1}, True},
+		{"Eq null", Condition{Op: Eq, FieldPaths: []Path{null, null}}, Unknown},
+		{"Eq known differ", Condition{Op: Eq, FieldPaths: []Path{a, b, null}}, False},
+		{"Neq", Condition{Op: Neq, FieldPaths: []Path{a, b}}, True},
+		{"Neq empty", Condition{Op: Neq}, False},
+		{"Eq enum", Condition{Op: Eq, FieldPaths: []Path{{"state"}}, Literal: "draft"}, True},
+		{"Eq nested", Condition{Op: Eq, FieldPaths: []Path{{"home", "city"}}, Literal: "Paris"}, True},
+		{"AndIsNull", Condition{Op: AndIsNull, FieldPaths: []Path{null, {"missing"}}}, True},
+		{"AndIsNull empty", Condition{Op: AndIsNull}, True},
+		{"OrIsNull", Condition{Op: OrIsNull, FieldPaths: []Path{a, null}}, True},
+		{"OrIsNull empty", Condition{Op: OrIsNull}, False},
+		{"AndNotNull", Condition{Op: AndNotNull, FieldPaths: []Path{a, null}}, False},
+		{"OrNotNull", Condition{Op: OrNotNull, FieldPaths: []Path{a, null}}, True},
+		{"In empty", Condition{Op: In}, True},
+		{"In", Condition{Op: In, FieldPaths: []Path{{"tags"}}, Literal: "y"}, True},
+		{"In not found", Condition{Op: In, FieldPaths: []Path{{"tags"}}, Literal: []string{"z"}}, False},
+		{"In empty operand", Condition{Op: In, FieldPaths: []Path{{"tags"}}, Literal: []string{}}, False},
+		{"In unknown", Condition{Op: In, FieldPaths: []Path{{"tags"}, null}}, Unknown},
+		{"In through list", Condition{Op: In, FieldPaths: []Path{{"lines", "n"}}, Literal: 2}, True},
+		{"NotIn", Condition{Op: NotIn, FieldPaths: []Path{{"tags"}}, Literal: "z"}, True},
+		{"Lt chain", Condition{Op: Lt, FieldPaths: []Path{a, b, c}}, True},
+		{"Lt one", Condition{Op: Lt, FieldPaths: []Path{c}}, True},
+		{"Nlt one", Condition{Op: Nlt, FieldPaths: []Path{c}}, False},
+		{"Lt literal last", Condition{Op: Lt, FieldPaths: []Path{a}, Literal: 0}, False},
+		{"Lt null", Condition{Op: Lt, FieldPaths: []Path{a, null}}, Unknown},
+		{"Lt null but false", Condition{Op: Lt, FieldPaths: []Path{null, c, b}}, False},
+		{"1<3<2 is false", Condition{Op: Lt, FieldPaths: []Path{a, c, b}}, False},
+		{"1>=3>=2 is false", Condition{Op: Gte, FieldPaths: []Path{a, c, b}}, False},
+		{"Ngte", Condition{Op: Ngte, FieldPaths: []Path{a, c, b}}, True},
+		{"Lte equal", Condition{Op: Lte, FieldPaths: []Path{a}, Literal: int64(1)}, True},
+		{"Gt", Condition{Op: Gt, FieldPaths: []Path{c, b, a}}, True},
+		{"Eq float int", Condition{Op: Eq, FieldPaths: []Path{{"float"}, a}}, True},
+		{"Eq float inexact int", Condition{Op: Eq, FieldPaths: []Path{{"float"}}, Literal: int64(1<<53 + 1)}, False},
+		{"Lt decimal int", Condition{Op: Lt, FieldPaths: []Path{a, {"price"}}, Literal: 2}, True},
+		{"Eq decimal float", Condition{Op: Eq, FieldPaths: []Path{{"price"}}, Literal: 1.5}, True},
+		{"child conditions", Condition{Op: And, Children: []Condition{
+			{Op: Lt, FieldPaths: []Path{a, b}},
+			{Op: Or, FieldPaths: []Path{no, yes}},
+		}}, True},
+		{"child unknown as null", Condition{Op: AndIsNull, Children: []Condition{
+			{Op: Eq, FieldPaths: []Path{null, a}},
+		}}, True},
+		{"PushUp ordering", must.V(MakeDirectedCondition(Lt, a, 2, c)), True},
+		{"PushUp ordering false", must.V(MakeDirectedCondition(Lt, a, 5, c)), False},
+		{"PushUp literal first", must.V(MakeDirectedCondition(Lt, 0, a, b)), True},
+	}
+	for _, tt := range tests {
+		got, err := tt.cond.Evaluate(row, nil)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+
+	_, err := Condition{Op: And, FieldPaths: []Path{a}}.Evaluate(row, nil)
+	assert.ErrorAs(t, err, &seederrors.ValueError{})
+	_, err = Condition{Op: Lt, FieldPaths: []Path{a, {"home"}}}.Evaluate(row, nil)
+	assert.Error(t, err)
+	_, err = Condition{Op: PushUp}.Evaluate(row, nil)
+	assert.Error(t, err)
+}
+
+func TestConditionEvaluateObjectValue(t *testing.T) {
+	text := testdomain.TextLineField()
+	ob := NewObjectValue()
+	require.NoError(t, SetField(ob, text, "hello", nil))
+	got, err := Condition{Op: Eq, FieldPaths: []Path{{text.Name}}, Literal: "hello"}.Evaluate(ob, nil)
+	require.NoError(t, err)
+	assert.Equal(t, True, got)
+
+	resolver := func(row any, path Path) (any, error) {
+		return len(path), nil
+	}
+	got, err = Condition{Op: Lt, FieldPaths: []Path{{"x"}, {"x", "y"}}}.Evaluate(nil, resolver)
+	require.NoError(t, err)
+	assert.Equal(t, True, got)
+}
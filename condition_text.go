@@ -0,0 +1,457 @@
+package seed
+
+import (
+	"encoding/base64"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// The text syntax of conditions is a nested list of operators and operands:
+//
+//	condition = op "(" [ operand { "," operand } ] ")"
+//	operand   = condition | path | literal
+//	path      = name { "." name }
+//	literal   = "true" | "false" | number | string | typed | list
+//	typed     = ( "t" | "d" | "b" ) string
+//	list      = "[" [ literal { "," literal } ] "]"
+//
+// Operators are named as printed by Op.String, such as And, NLte and AndIsNull. Operands are
+// evaluated in the order they are written, as in MakeDirectedCondition.
+//
+// Literals are typed: numbers are int64, *big.Int if too large for int64, or float64 if they have
+// a decimal point or an exponent. Strings are quoted as in Go. Typed strings hold time.Time in
+// RFC 3339 (t"2006-01-02T15:04:05Z"), decimal.Decimal (d"1.23"), and []byte in standard base64
+// (b"AQI="). Lists are []any.
+//
+// Paths can not start with a field named true, false or null, as they are read as literals.
+//
+// For example: Or(Lt(event.start_time, t"2023-01-01T00:00:00Z"), In(status, ["draft", "archived"]))
+
+// ParseCondition parses a condition in the text syntax, errors report their position in s.
+func ParseCondition(s string) (Condition, error) {
+	p := &conditionParser{input: s}
+	p.skipSpace()
+	start := p.pos
+	c, err := p.condition()
+	if err != nil {
+		return Condition{}, err
+	}
+	if c.Op == PushUp {
+		return Condition{}, p.errorAt(start, "PushUp can not be the root condition")
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return Condition{}, p.errorAt(p.pos, "unexpected %q after condition", p.rest(10))
+	}
+	return c, nil
+}
+
+type conditionParser struct {
+	input string
+	pos   int
+}
+
+func (p *conditionParser) errorAt(pos int, format string, a ...any) error {
+	return seederrors.NewSyntaxError(p.input, pos, format, a...)
+}
+
+func (p *conditionParser) rest(n int) string {
+	r := p.input[p.pos:]
+	if len(r) > n {
+		return r[:n] + "..."
+	}
+	return r
+}
+
+func (p *conditionParser) skipSpace() {
+	for p.pos < len(p.input) && strings.ContainsRune(" \t\r\n", rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *conditionParser) peek() byte {
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+func (p *conditionParser) expect(b byte) error {
+	p.skipSpace()
+	if p.peek() != b {
+		if p.pos >= len(p.input) {
+			return p.errorAt(p.pos, "expected %q, found end of input", b)
+		}
+		return p.errorAt(p.pos, "expected %q, found %q", b, p.rest(10))
+	}
+	p.pos++
+	return nil
+}
+
+func isNameStart(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+func isNamePart(b byte) bool {
+	return isNameStart(b) || '0' <= b && b <= '9' || b == '_'
+}
+
+func (p *conditionParser) name() string {
+	start := p.pos
+	if !isNameStart(p.peek()) {
+		return ""
+	}
+	for p.pos < len(p.input) && isNamePart(p.input[p.pos]) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+// condition parses an operator with its operands.
+func (p *conditionParser) condition() (Condition, error) {
+	start := p.pos
+	name := p.name()
+	op, ok := parseOp(name)
+	if !ok {
+		if name == "" {
+			return Condition{}, p.errorAt(start, "expected an operator, found %q", p.rest(10))
+		}
+		return Condition{}, p.errorAt(start, "unknown operator %q", name)
+	}
+	err := p.expect('(')
+	if err != nil {
+		return Condition{}, err
+	}
+	var operands []any
+	p.skipSpace()
+	if p.peek() == ')' {
+		p.pos++
+		return makeCondition(op), nil
+	}
+	for {
+		v, err := p.operand()
+		if err != nil {
+			return Condition{}, err
+		}
+		operands = append(operands, v)
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+			continue
+		case ')':
+			p.pos++
+			return makeCondition(op, operands...), nil
+		}
+		return Condition{}, p.errorAt(p.pos, "expected \",\" or \")\", found %q", p.rest(10))
+	}
+}
+
+func parseOp(name string) (Op, bool) {
+	for op, s := range _opString {
+		if s == name {
+			return Op(op), true
+		}
+	}
+	return 0, false
+}
+
+// operand parses a condition, a path or a literal.
+func (p *conditionParser) operand() (any, error) {
+	p.skipSpace()
+	start := p.pos
+	name := p.name()
+	switch {
+	case name == "", name == "true", name == "false", name == "null", len(name) == 1 && p.peek() == '"':
+		p.pos = start
+		return p.literal()
+	}
+	p.skipSpace()
+	if p.peek() == '(' {
+		p.pos = start
+		return p.condition()
+	}
+	path := Path{CodeName(name)}
+	for p.peek() == '.' {
+		p.pos++
+		part := p.name()
+		if part == "" {
+			return nil, p.errorAt(p.pos, "expected a field name after \".\"")
+		}
+		path = append(path, CodeName(part))
+	}
+	return path, nil
+}
+
+func (p *conditionParser) literal() (any, error) {
+	p.skipSpace()
+	start := p.pos
+	switch b := p.peek(); {
+	case b == '"':
+		return p.quoted()
+	case b == '[':
+		return p.list()
+	case b == '-' || b == '+' || '0' <= b && b <= '9':
+		return p.number()
+	case b == 0:
+		return nil, p.errorAt(start, "expected an operand, found end of input")
+	}
+	switch name := p.name(); {
+	case name == "true", name == "false":
+		return name == "true", nil
+	case name == "null":
+		return nil, p.errorAt(start, "null can not be used as a literal, use *IsNull or *NotNull operators")
+	case (name == "t" || name == "d" || name == "b") && p.peek() == '"':
+		s, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		v, err := parseTypedLiteral(name[0], s)
+		if err != nil {
+			return nil, p.errorAt(start, "%v", err)
+		}
+		return v, nil
+	}
+	p.pos = start
+	return nil, p.errorAt(start, "expected a literal, found %q", p.rest(10))
+}
+
+func parseTypedLiteral(prefix byte, s string) (any, error) {
+	switch prefix {
+	case 't':
+		return time.Parse(time.RFC3339Nano, s)
+	case 'd':
+		return decimal.NewFromString(s)
+	}
+	return base64.StdEncoding.DecodeString(s)
+}
+
+func (p *conditionParser) quoted() (string, error) {
+	start := p.pos
+	if p.peek() != '"' {
+		return "", p.errorAt(start, "expected a quoted string")
+	}
+	for i := start + 1; i < len(p.input); i++ {
+		switch p.input[i] {
+		case '\\':
+			i++
+		case '"':
+			s, err := strconv.Unquote(p.input[start : i+1])
+			if err != nil {
+				return "", p.errorAt(start, "invalid string: %v", err)
+			}
+			p.pos = i + 1
+			return s, nil
+		}
+	}
+	return "", p.errorAt(start, "string is not terminated")
+}
+
+func (p *conditionParser) number() (any, error) {
+	start := p.pos
+	p.pos++ // sign or first digit
+	isFloat := false
+	for p.pos < len(p.input) {
+		b := p.input[p.pos]
+		switch {
+		case '0' <= b && b <= '9':
+		case b == '.' || b == 'e' || b == 'E':
+			isFloat = true
+		case (b == '-' || b == '+') && (p.input[p.pos-1] == 'e' || p.input[p.pos-1] == 'E'):
+		default:
+			return p.numberValue(start, isFloat)
+		}
+		p.pos++
+	}
+	return p.numberValue(start, isFloat)
+}
+
+func (p *conditionParser) numberValue(start int, isFloat bool) (any, error) {
+	s := p.input[start:p.pos]
+	if isFloat {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, p.errorAt(start, "invalid number %q", s)
+		}
+		return f, nil
+	}
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, p.errorAt(start, "invalid number %q", s)
+	}
+	if i.IsInt64() {
+		return i.Int64(), nil
+	}
+	return i, nil
+}
+
+func (p *conditionParser) list() (any, error) {
+	p.pos++ // [
+	out := []any{}
+	p.skipSpace()
+	if p.peek() == ']' {
+		p.pos++
+		return out, nil
+	}
+	for {
+		v, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+			continue
+		case ']':
+			p.pos++
+			return out, nil
+		}
+		return nil, p.errorAt(p.pos, "expected \",\" or \"]\", found %q", p.rest(10))
+	}
+}
+
+// FormatCondition prints c in the text syntax of ParseCondition. Operands are printed in the order of
+// Children, FieldPaths and Literal.
+//
+// Parsing the result returns c only if its literals are of the types returned by ParseCondition.
+// Other literals parse to the type of the syntax they are printed in: int to int64, CodeName to
+// string, other lists to []any, and time.Time in a named Location to the same instant in a fixed
+// offset zone.
+func FormatCondition(c Condition) (string, error) {
+	var sb strings.Builder
+	err := formatCondition(&sb, c)
+	if err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func formatCondition(sb *strings.Builder, c Condition) error {
+	sb.WriteString(c.Op.String())
+	sb.WriteByte('(')
+	first := true
+	sep := func() {
+		if !first {
+			sb.WriteString(", ")
+		}
+		first = false
+	}
+	for _, child := range c.Children {
+		sep()
+		err := formatCondition(sb, child)
+		if err != nil {
+			return err
+		}
+	}
+	for _, path := range c.FieldPaths {
+		sep()
+		err := formatPath(sb, path)
+		if err != nil {
+			return err
+		}
+	}
+	if c.Literal != nil {
+		sep()
+		err := formatLiteral(sb, c.Literal)
+		if err != nil {
+			return err
+		}
+	}
+	sb.WriteByte(')')
+	return nil
+}
+
+func formatPath(sb *strings.Builder, path Path) error {
+	if len(path) == 0 {
+		return seederrors.NewSystemError("empty field path can not be formatted")
+	}
+	for i, name := range path {
+		if name == "" || !isNameStart(name[0]) || strings.IndexFunc(string(name), func(r rune) bool {
+			return r >= utf8.RuneSelf || !isNamePart(byte(r))
+		}) >= 0 {
+			return seederrors.NewSystemError("field name %q can not be formatted", name)
+		}
+		if i == 0 && (name == "true" || name == "false" || name == "null") {
+			return seederrors.NewSystemError("field path starting with %q can not be formatted, it would be read as a literal", name)
+		}
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(string(name))
+	}
+	return nil
+}
+
+//nolint:cyclop // one case per literal type
+func formatLiteral(sb *strings.Builder, v any) error {
+	switch vt := v.(type) {
+	case bool:
+		sb.WriteString(strconv.FormatBool(vt))
+	case string:
+		sb.WriteString(strconv.Quote(vt))
+	case CodeName:
+		sb.WriteString(strconv.Quote(string(vt)))
+	case int:
+		sb.WriteString(strconv.Itoa(vt))
+	case int64:
+		sb.WriteString(strconv.FormatInt(vt, 10))
+	case *big.Int:
+		sb.WriteString(vt.String())
+	case float64:
+		if math.IsNaN(vt) || math.IsInf(vt, 0) {
+			return seederrors.NewSystemError("literal %v can not be formatted", vt)
+		}
+		s := strconv.FormatFloat(vt, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".e") {
+			s += ".0"
+		}
+		sb.WriteString(s)
+	case decimal.Decimal:
+		sb.WriteString("d" + strconv.Quote(decimalString(vt)))
+	case time.Time:
+		sb.WriteString("t" + strconv.Quote(vt.Format(time.RFC3339Nano)))
+	case []byte:
+		sb.WriteString("b" + strconv.Quote(base64.StdEncoding.EncodeToString(vt)))
+	default:
+		return formatList(sb, v)
+	}
+	return nil
+}
+
+// decimalString formats d without dropping trailing zeros, which are significant in decimals.
+func decimalString(d decimal.Decimal) string {
+	if d.Exponent() < 0 {
+		return d.StringFixed(-d.Exponent())
+	}
+	return d.String()
+}
+
+func formatList(sb *strings.Builder, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return seederrors.NewSystemError("literal of %T can not be formatted", v)
+	}
+	sb.WriteByte('[')
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		err := formatLiteral(sb, rv.Index(i).Interface())
+		if err != nil {
+			return err
+		}
+	}
+	sb.WriteByte(']')
+	return nil
+}
@@ -0,0 +1,95 @@
+package seed_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
+
+	. "github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+func TestParseCondition(t *testing.T) {
+	start := NewPath("event", "start_time")
+	got, err := ParseCondition(`Or(
+		Lt(event.start_time, t"2023-01-01T00:00:00Z"),
+		In(status, ["draft", "archived"]),
+		AndIsNull(note)
+	)`)
+	require.NoError(t, err)
+	assert.Equal(t, Condition{Op: Or, Children: []Condition{
+		{Op: Lt, FieldPaths: []Path{start}, Literal: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Op: In, FieldPaths: []Path{{"status"}}, Literal: []any{"draft", "archived"}},
+		{Op: AndIsNull, FieldPaths: []Path{{"note"}}},
+	}}, got)
+
+	got, err = ParseCondition(`NLte(1, a, 2.5, b)`)
+	require.NoError(t, err)
+	assert.Equal(t, must.V(MakeDirectedCondition(Nlte, int64(1), NewPath("a"), 2.5, NewPath("b"))), got)
+
+	got, err = ParseCondition(`Eq(n, 123456789012345678901234567890)`)
+	require.NoError(t, err)
+	want, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	assert.Equal(t, want, got.Literal)
+}
+
+func TestConditionTextRoundTrip(t *testing.T) {
+	a := NewPath("a")
+	for _, c := range []Condition{
+		{Op: And},
+		{Op: Nand, Children: []Condition{{Op: Eq, FieldPaths: []Path{a}, Literal: true}}, FieldPaths: []Path{{"p"}}},
+		must.V(MakeDirectedCondition(Lt, int64(1), a, int64(3), NewPath("b", "c"))),
+		must.V(MakeDirectedCondition(Ngte, a, decimal.RequireFromString("1.50"))),
+		{Op: NotIn, FieldPaths: []Path{a}, Literal: []any{int64(-1), 1e100, "x\n\"y\"", []byte{1, 2}, false}},
+		{Op: OrNotNull, Children: []Condition{{Op: PushUp, FieldPaths: []Path{a}}}, Literal: time.Date(2000, 1, 2, 3, 4, 5, 6, time.FixedZone("", 3600))},
+	} {
+		text, err := FormatCondition(c)
+		require.NoError(t, err)
+		got, err := ParseCondition(text)
+		require.NoError(t, err, text)
+		assert.Equal(t, c, got, text)
+	}
+	text, err := FormatCondition(must.V(MakeDirectedCondition(Lt, int64(1), a, float64(2))))
+	require.NoError(t, err)
+	assert.Equal(t, "Lt(PushUp(1), a, 2.0)", text)
+	_, err = FormatCondition(Condition{Op: Eq, Literal: struct{}{}})
+	assert.Error(t, err)
+
+	for _, name := range []CodeName{"true", "false", "null"} {
+		_, err = FormatCondition(Condition{Op: Eq, FieldPaths: []Path{{name}}, Literal: true})
+		assert.Error(t, err, name)
+		_, err = FormatCondition(Condition{Op: Eq, FieldPaths: []Path{{name, "x"}}, Literal: true})
+		assert.Error(t, err, name)
+		c := Condition{Op: Eq, FieldPaths: []Path{{"x", name}}, Literal: true}
+		text, err := FormatCondition(c)
+		require.NoError(t, err)
+		assert.Equal(t, c, must.V(ParseCondition(text)), text)
+	}
+}
+
+func TestParseConditionErrors(t *testing.T) {
+	for input, want := range map[string]seederrors.SyntaxError{
+		``:                     {Offset: 0, Line: 1, Column: 1},
+		`Foo(a)`:               {Offset: 0, Line: 1, Column: 1},
+		`and(a)`:               {Offset: 0, Line: 1, Column: 1},
+		`And(a`:                {Offset: 5, Line: 1, Column: 6},
+		"And(\n  Eq(a, null))": {Offset: 13, Line: 2, Column: 9},
+		`Eq(a, "x)`:            {Offset: 6, Line: 1, Column: 7},
+		`Eq(a, t"yesterday")`:  {Offset: 6, Line: 1, Column: 7},
+		`Eq(a.)`:               {Offset: 5, Line: 1, Column: 6},
+		`And() Or()`:           {Offset: 6, Line: 1, Column: 7},
+		`PushUp(a)`:            {Offset: 0, Line: 1, Column: 1},
+		`In(a, [1 2])`:         {Offset: 9, Line: 1, Column: 10},
+	} {
+		_, err := ParseCondition(input)
+		var syntaxErr seederrors.SyntaxError
+		require.ErrorAs(t, err, &syntaxErr, input)
+		syntaxErr.Message = ""
+		assert.Equal(t, want, syntaxErr, input)
+	}
+}
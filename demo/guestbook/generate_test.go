@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/demo/guestbook/model"
+	"github.com/xiegeo/seed/gogen"
+)
+
+// TestGenerated checks that guestbook.json and the generated model are up to date with Domain().
+// Run go generate after updating guestbook.json.
+func TestGenerated(t *testing.T) {
+	data, err := json.MarshalIndent(Domain(), "", "\t")
+	require.NoError(t, err)
+	file, err := os.ReadFile("guestbook.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(data)+"\n", string(file))
+
+	var buf bytes.Buffer
+	require.NoError(t, gogen.Generate(&buf, Domain(), gogen.Options{PackageName: "model"}))
+	file, err = os.ReadFile("model/model_gen.go")
+	require.NoError(t, err)
+	assert.Equal(t, buf.String(), string(file))
+}
+
+func TestModelMap(t *testing.T) {
+	guest := model.Guest{
+		Time:           time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		Name:           "Alice",
+		NumberOfGuests: 2,
+	}
+	m := guest.ToMap()
+	require.NoError(t, seed.CheckValues(Guest(), m))
+	assert.Equal(t, "Alice", m[model.FieldGuestName])
+	back, err := model.GuestFromMap(m)
+	require.NoError(t, err)
+	assert.Equal(t, guest, back)
+
+	delete(m, model.FieldGuestName)
+	_, err = model.GuestFromMap(m)
+	assert.Error(t, err)
+}
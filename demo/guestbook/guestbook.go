@@ -1,3 +1,5 @@
+//go:generate go run ../../cmd/seedgen -in guestbook.json -out model/model_gen.go -package model
+
 package main
 
 import (
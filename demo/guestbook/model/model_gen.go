@@ -0,0 +1,113 @@
+// Code generated by seedgen from domain "guestbook". DO NOT EDIT.
+
+package model
+
+import (
+	"time"
+
+	"github.com/xiegeo/seed"
+)
+
+// Names of objects in domain "guestbook".
+const (
+	ObjectGuest seed.CodeName = "guest"
+	ObjectEvent seed.CodeName = "event"
+)
+
+// Names of fields in Guest.
+const (
+	FieldGuestTime           seed.CodeName = "time"
+	FieldGuestName           seed.CodeName = "name"
+	FieldGuestNumberOfGuests seed.CodeName = "number_of_guests"
+	FieldGuestNote           seed.CodeName = "note"
+)
+
+// Guest holds the values of object "guest".
+type Guest struct {
+	Time           time.Time
+	Name           string
+	NumberOfGuests int64
+	Note           string
+}
+
+// ToMap returns the values of v keyed by field names, as accepted by persistence layers.
+func (v *Guest) ToMap() map[seed.CodeName]any {
+	m := make(map[seed.CodeName]any, 4)
+	m[FieldGuestTime] = v.Time
+	m[FieldGuestName] = v.Name
+	m[FieldGuestNumberOfGuests] = v.NumberOfGuests
+	m[FieldGuestNote] = v.Note
+	return m
+}
+
+// GuestFromMap reads values in the form of ToMap, such as values read from a persistence layer.
+func GuestFromMap(m map[seed.CodeName]any) (Guest, error) {
+	var v Guest
+	var err error
+	v.Time, err = seed.MapValue[time.Time](m, FieldGuestTime)
+	if err != nil {
+		return v, err
+	}
+	v.Name, err = seed.MapValue[string](m, FieldGuestName)
+	if err != nil {
+		return v, err
+	}
+	v.NumberOfGuests, err = seed.MapValue[int64](m, FieldGuestNumberOfGuests)
+	if err != nil {
+		return v, err
+	}
+	v.Note, err = seed.MapValue[string](m, FieldGuestNote)
+	if err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+// Names of fields in Event.
+const (
+	FieldEventStartTime         seed.CodeName = "start_time"
+	FieldEventEndTime           seed.CodeName = "end_time"
+	FieldEventPublish           seed.CodeName = "publish"
+	FieldEventMaxNumberOfGuests seed.CodeName = "max_number_of_guests"
+)
+
+// Event holds the values of object "event".
+type Event struct {
+	StartTime         time.Time
+	EndTime           time.Time
+	Publish           bool
+	MaxNumberOfGuests int64 // Compacity
+}
+
+// ToMap returns the values of v keyed by field names, as accepted by persistence layers.
+func (v *Event) ToMap() map[seed.CodeName]any {
+	m := make(map[seed.CodeName]any, 4)
+	m[FieldEventStartTime] = v.StartTime
+	m[FieldEventEndTime] = v.EndTime
+	m[FieldEventPublish] = v.Publish
+	m[FieldEventMaxNumberOfGuests] = v.MaxNumberOfGuests
+	return m
+}
+
+// EventFromMap reads values in the form of ToMap, such as values read from a persistence layer.
+func EventFromMap(m map[seed.CodeName]any) (Event, error) {
+	var v Event
+	var err error
+	v.StartTime, err = seed.MapValue[time.Time](m, FieldEventStartTime)
+	if err != nil {
+		return v, err
+	}
+	v.EndTime, err = seed.MapValue[time.Time](m, FieldEventEndTime)
+	if err != nil {
+		return v, err
+	}
+	v.Publish, err = seed.MapValue[bool](m, FieldEventPublish)
+	if err != nil {
+		return v, err
+	}
+	v.MaxNumberOfGuests, err = seed.MapValue[int64](m, FieldEventMaxNumberOfGuests)
+	if err != nil {
+		return v, err
+	}
+	return v, nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/xiegeo/must"
 
 	"github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/demo/guestbook/model"
 	"github.com/xiegeo/seed/persistence/sqldb"
 	"github.com/xiegeo/seed/seedfake"
 
@@ -59,3 +60,25 @@ func TestAddDomain(t *testing.T) {
 	}
 	assert.Equal(t, 64, added)
 }
+
+func TestInsertModel(t *testing.T) {
+	ctx := context.TODO()
+	rawDB, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, rawDB.Close())
+	}()
+	db, err := sqldb.New(rawDB, sqldb.Sqlite)
+	require.NoError(t, err)
+	require.NoError(t, db.AddDomain(ctx, Domain()))
+	event := model.Event{
+		StartTime:         timeWithMinute(t, "2006-01-02T15:00"),
+		EndTime:           timeWithMinute(t, "2006-01-02T16:00"),
+		Publish:           true,
+		MaxNumberOfGuests: 100,
+	}
+	err = db.InsertObjects(ctx, map[seed.CodeName]any{
+		model.ObjectEvent: []map[seed.CodeName]any{event.ToMap()},
+	})
+	require.NoError(t, err)
+}
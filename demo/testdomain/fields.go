@@ -278,3 +278,38 @@ func ListOf(f *seed.Field, listSetting seed.ListSetting) *seed.Field {
 		FieldTypeSetting: listSetting,
 	}
 }
+
+func Decimal64() *seed.Field {
+	return &seed.Field{
+		Thing: seed.Thing{
+			Name: "decimal_64",
+			Label: seed.I18n[string]{
+				language.English: "Decimal (d64)",
+			},
+		},
+		FieldType: seed.Real,
+		FieldTypeSetting: seed.RealSetting{
+			Standard: seed.Decimal64,
+		},
+	}
+}
+
+func Enumeration() *seed.Field {
+	return &seed.Field{
+		Thing: seed.Thing{
+			Name: "status",
+			Label: seed.I18n[string]{
+				language.English: "Status",
+				language.Chinese: "状态",
+			},
+		},
+		FieldType: seed.Enumeration,
+		FieldTypeSetting: seed.EnumerationSetting{
+			Values: []seed.EnumerationValue{
+				{Thing: seed.Thing{Name: "draft", Label: seed.I18n[string]{language.English: "Draft", language.Chinese: "草稿"}}, Order: 1},
+				{Thing: seed.Thing{Name: "published", Label: seed.I18n[string]{language.English: "Published", language.Chinese: "已发布"}}, Order: 2},
+				{Thing: seed.Thing{Name: "archived", Label: seed.I18n[string]{language.English: "Archived", language.Chinese: "已归档"}}, Order: 3},
+			},
+		},
+	}
+}
@@ -0,0 +1,231 @@
+package seed
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/xiegeo/must"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// ChangeAction describes what happened to a thing between two domain versions.
+type ChangeAction int8
+
+const (
+	ChangeAdded ChangeAction = iota + 1
+	ChangeRemoved
+	ChangeModified
+)
+
+var _changeActionSymbol = []string{"?", "+", "-", "~"}
+
+func (a ChangeAction) String() string {
+	if a < 0 || int(a) >= len(_changeActionSymbol) {
+		return fmt.Sprintf("ChangeAction(%d)", a)
+	}
+	return _changeActionSymbol[a]
+}
+
+// Coverage describes how the values allowed by a new field relates to the values allowed by the old field.
+type Coverage int8
+
+const (
+	CoverageNone         Coverage = iota // not a modified field
+	CoverageSame                         // both versions allow the same values
+	CoverageWidened                      // new version allows all old values, and maybe more
+	CoverageNarrowed                     // old version allows all new values, and maybe more
+	CoverageIncompatible                 // each version allows values the other does not
+)
+
+var _coverageStringer = []string{"", "same", "widened", "narrowed", "incompatible"}
+
+func (c Coverage) String() string {
+	if c < 0 || int(c) >= len(_coverageStringer) {
+		return fmt.Sprintf("Coverage(%d)", c)
+	}
+	return _coverageStringer[c]
+}
+
+// Change is a single difference found by Diff.
+type Change struct {
+	Type     seederrors.ThingType
+	Path     Path // from object name to the changed thing, unnamed identities and ranges are keyed by their fields.
+	Action   ChangeAction
+	Coverage Coverage // only set for modified fields
+	Old, New any      // ObjectGetter, *Field, Identity or Range; Old is nil if added, New is nil if removed.
+}
+
+func (c Change) String() string {
+	if c.Coverage == CoverageNone {
+		return fmt.Sprintf("%s %s %s", c.Action, c.Type, joinS(c.Path, "."))
+	}
+	return fmt.Sprintf("%s %s %s (%s)", c.Action, c.Type, joinS(c.Path, "."), c.Coverage)
+}
+
+// ChangeSet lists changes in a stable order: changes of old things in their logical order come first,
+// followed by things only found in the new version.
+type ChangeSet []Change
+
+func (cs ChangeSet) String() string {
+	lines := make([]string, len(cs))
+	for i, c := range cs {
+		lines[i] = c.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Narrowed returns true if any change could make existing data invalid,
+// such as removed objects and fields, or narrowed field settings.
+func (cs ChangeSet) Narrowed() bool {
+	for _, c := range cs {
+		switch {
+		case c.Action == ChangeRemoved && (c.Type == seederrors.ThingTypeObject || c.Type == seederrors.ThingTypeField),
+			c.Action == ChangeAdded && (c.Type == seederrors.ThingTypeIdentity || c.Type == seederrors.ThingTypeRange),
+			c.Coverage == CoverageNarrowed, c.Coverage == CoverageIncompatible:
+			return true
+		}
+	}
+	return false
+}
+
+func joinS[S ~string](elems []S, sep string) string {
+	ss := make([]string, len(elems))
+	for i, e := range elems {
+		ss[i] = string(e)
+	}
+	return strings.Join(ss, sep)
+}
+
+// Diff reports objects, fields, identities and ranges that were added, removed or modified
+// from one domain version to the next. Changes inside combination fields are reported with the
+// combination field in the path. Changes to the domain itself, such as labels, are not reported.
+func Diff(from, to DomainGetter) ChangeSet {
+	var cs ChangeSet
+	newObjects := to.GetObjects()
+	must.NoError(from.GetObjects().RangeLogical(func(cn CodeName, ob ObjectGetter) error {
+		path := Path{cn}
+		ob2, ok := newObjects.Get(cn)
+		if !ok {
+			cs = append(cs, Change{Type: seederrors.ThingTypeObject, Path: path, Action: ChangeRemoved, Old: ob})
+			return nil
+		}
+		if !sameThing(ob, ob2) {
+			cs = append(cs, Change{Type: seederrors.ThingTypeObject, Path: path, Action: ChangeModified, Old: ob, New: ob2})
+		}
+		cs = cs.diffFieldGroup(path, ob, ob2)
+		return nil
+	}))
+	must.NoError(newObjects.RangeLogical(func(cn CodeName, ob ObjectGetter) error {
+		if _, ok := from.GetObjects().Get(cn); !ok {
+			cs = append(cs, Change{Type: seederrors.ThingTypeObject, Path: Path{cn}, Action: ChangeAdded, New: ob})
+		}
+		return nil
+	}))
+	return cs
+}
+
+func withName(path Path, cn CodeName) Path {
+	out := make(Path, 0, len(path)+1)
+	return append(append(out, path...), cn)
+}
+
+func sameThing(a, b ThingGetter) bool {
+	return a.GetName() == b.GetName() &&
+		reflect.DeepEqual(NewI18n(a.GetLabel()), NewI18n(b.GetLabel())) &&
+		reflect.DeepEqual(NewI18n(a.GetDescription()), NewI18n(b.GetDescription()))
+}
+
+func (cs ChangeSet) diffFieldGroup(path Path, g, g2 FieldGroupGetter) ChangeSet {
+	fields2 := g2.GetFields()
+	must.NoError(g.GetFields().RangeLogical(func(cn CodeName, f *Field) error {
+		fieldPath := withName(path, cn)
+		f2, ok := fields2.Get(cn)
+		if !ok {
+			cs = append(cs, Change{Type: seederrors.ThingTypeField, Path: fieldPath, Action: ChangeRemoved, Old: f})
+			return nil
+		}
+		coverage := FieldCoverage(f, f2)
+		if coverage != CoverageSame || !sameThing(f, f2) || !reflect.DeepEqual(f.Default, f2.Default) {
+			cs = append(cs, Change{Type: seederrors.ThingTypeField, Path: fieldPath, Action: ChangeModified, Coverage: coverage, Old: f, New: f2})
+		}
+		combination, ok := f.FieldTypeSetting.(CombinationSetting)
+		combination2, ok2 := f2.FieldTypeSetting.(CombinationSetting)
+		if ok && ok2 {
+			cs = cs.diffFieldGroup(fieldPath, &combination, &combination2)
+		}
+		return nil
+	}))
+	must.NoError(fields2.RangeLogical(func(cn CodeName, f *Field) error {
+		if _, ok := g.GetFields().Get(cn); !ok {
+			cs = append(cs, Change{Type: seederrors.ThingTypeField, Path: withName(path, cn), Action: ChangeAdded, New: f})
+		}
+		return nil
+	}))
+	cs = diffKeyed(cs, seederrors.ThingTypeIdentity, path, g.GetIdentities(), g2.GetIdentities(), identityKey)
+	return diffKeyed(cs, seederrors.ThingTypeRange, path, g.GetRanges(), g2.GetRanges(), rangeKey)
+}
+
+// identityKey keys an identity by name, or by its fields if not named.
+func identityKey(id Identity) CodeName {
+	if id.Name != "" {
+		return id.Name
+	}
+	keys := make([]CodeName, 0, len(id.Fields)+len(id.Ranges))
+	keys = append(keys, id.Fields...)
+	for _, r := range id.Ranges {
+		keys = append(keys, rangeKey(r))
+	}
+	return CodeName("(" + joinS(keys, ",") + ")")
+}
+
+// rangeKey keys a range by name, or by its start and end fields if not named.
+func rangeKey(r Range) CodeName {
+	if r.Name != "" {
+		return r.Name
+	}
+	return "(" + r.Start + ".." + r.End + ")"
+}
+
+func diffKeyed[T any](cs ChangeSet, t seederrors.ThingType, path Path, olds, news []T, key func(T) CodeName) ChangeSet {
+	newByKey := make(map[CodeName]T, len(news))
+	for _, v := range news {
+		newByKey[key(v)] = v
+	}
+	oldKeys := make(map[CodeName]struct{}, len(olds))
+	for _, v := range olds {
+		k := key(v)
+		oldKeys[k] = struct{}{}
+		v2, ok := newByKey[k]
+		switch {
+		case !ok:
+			cs = append(cs, Change{Type: t, Path: withName(path, k), Action: ChangeRemoved, Old: v})
+		case !reflect.DeepEqual(v, v2):
+			cs = append(cs, Change{Type: t, Path: withName(path, k), Action: ChangeModified, Old: v, New: v2})
+		}
+	}
+	for _, v := range news {
+		k := key(v)
+		if _, ok := oldKeys[k]; !ok {
+			cs = append(cs, Change{Type: t, Path: withName(path, k), Action: ChangeAdded, New: v})
+		}
+	}
+	return cs
+}
+
+// FieldCoverage compares the values allowed after a field changed from one version to the next,
+// using field type settings, Nullable and IsI18n. Labels and descriptions are ignored.
+func FieldCoverage(from, to *Field) Coverage {
+	widened := to.Covers(from)
+	narrowed := from.Covers(to)
+	switch {
+	case widened && narrowed:
+		return CoverageSame
+	case widened:
+		return CoverageWidened
+	case narrowed:
+		return CoverageNarrowed
+	}
+	return CoverageIncompatible
+}
@@ -0,0 +1,51 @@
+package seed_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xiegeo/must"
+	"golang.org/x/text/language"
+
+	. "github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/demo/testdomain"
+)
+
+func TestDiff(t *testing.T) {
+	from := testdomain.DomainLevel1()
+	assert.Empty(t, Diff(from, testdomain.DomainLevel1()))
+
+	level0 := testdomain.ObjLevel0()
+	level0.Label = I18n[string]{language.English: "Level 0"}
+	level0.Identities = append(level0.Identities, Identity{Fields: []CodeName{testdomain.Bool().Name}})
+	level0.Fields = must.V(level0.Fields.NewMap(func(f *Field) (*Field, error) {
+		switch f.Name {
+		case testdomain.TextLineField().Name:
+			f.FieldTypeSetting = StringSetting{MaxCodePoints: 20, IsSingleLine: true}
+		case testdomain.JSInteger().Name:
+			f.FieldTypeSetting = IntegerSetting{Min: big.NewInt(0), Max: big.NewInt(10)}
+		case testdomain.Bool().Name:
+			f.Label = I18n[string]{language.English: "Yes or No"}
+		case testdomain.DateTimeSec().Name:
+			f.Nullable = true
+			f.FieldTypeSetting = must.V(GetFieldTypeSetting[TimeStampSetting](testdomain.DateTimeMill()))
+		}
+		return f, nil
+	}))
+	must.NoError(level0.Fields.AddValue(testdomain.Float64()))
+	to := must.V(NewDomain(Thing{Name: "test_level_1"}, level0, testdomain.ObjLevel0List(), testdomain.ObjLevel0Identities()))
+
+	changes := Diff(from, to)
+	assert.Equal(t, `~ object level_0
+~ field level_0.text_10 (widened)
+~ field level_0.bool (same)
+~ field level_0.datetime_sec_9999 (widened)
+~ field level_0.integer_js (narrowed)
++ field level_0.float_64
++ identity level_0.(bool)
+- object time_stamp_common
++ object level_0_ids`, changes.String())
+	assert.True(t, changes.Narrowed())
+	assert.False(t, Diff(testdomain.DomainLevel0base(), testdomain.DomainLevel1()).Narrowed(), "only objects are added")
+}
@@ -9,8 +9,8 @@ type CodeName string
 // Thing is a base type for anything that can be identified.
 type Thing struct {
 	Name        CodeName     // name is the long term api name of the thing, name is locally unique.
-	Label       I18n[string] // used for displaying input label or column header.
-	Description I18n[string] // used for displaying addition information.
+	Label       I18n[string] `json:",omitempty"` // used for displaying input label or column header.
+	Description I18n[string] `json:",omitempty"` // used for displaying addition information.
 }
 
 // Domain holds a collection of objects, equivalent to all create table statements in a SQL database.
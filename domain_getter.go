@@ -62,6 +62,9 @@ type FieldGroupGetter interface {
 }
 
 func (g *FieldGroup) GetFields() dictionary.Getter[CodeName, *Field] {
+	if g.Fields == nil {
+		return nil // don't return a typed nil
+	}
 	return g.Fields
 }
 
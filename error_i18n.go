@@ -0,0 +1,107 @@
+package seed
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// errorLocalizer renders errors with messages of seederrors.Catalog and labels of a domain.
+type errorLocalizer struct {
+	picker  *Picker
+	printer *message.Printer
+	labels  map[string]I18nGetter[string]
+}
+
+// ErrorLocalizer returns a localizer of seederrors for users of the language picked by p.
+// Code names of d, such as names of objects and fields, are shown by their labels if they have any.
+// If d is nil, code names are shown as they are.
+//
+// Code names are looked up across the whole domain, so a name used by fields of different objects is
+// shown by the label of the first of them.
+func ErrorLocalizer(p *Picker, d DomainGetter) seederrors.Localizer {
+	if p == nil {
+		p = _lastPicker
+	}
+	available := make(I18n[struct{}])
+	for _, tag := range seederrors.Catalog.Languages() {
+		available[tag] = struct{}{}
+	}
+	tag, ok := Pick[struct{}](p, available)
+	if !ok {
+		tag = language.English
+	}
+	l := errorLocalizer{
+		picker:  p,
+		printer: message.NewPrinter(tag, message.Catalog(seederrors.Catalog)),
+		labels:  make(map[string]I18nGetter[string]),
+	}
+	if d != nil {
+		l.addThing(d)
+		for _, ob := range d.GetObjects().Values() {
+			l.addThing(ob)
+			l.addFieldGroup(ob)
+		}
+	}
+	return l
+}
+
+func (l errorLocalizer) addThing(t ThingGetter) {
+	name := string(t.GetName())
+	if _, ok := l.labels[name]; ok || t.GetLabel() == nil || t.GetLabel().Count() == 0 {
+		return
+	}
+	l.labels[name] = t.GetLabel()
+}
+
+func (l errorLocalizer) addFieldGroup(g FieldGroupGetter) {
+	if g.GetFields() != nil {
+		for _, f := range g.GetFields().Values() {
+			l.addThing(f)
+			if c, ok := f.FieldTypeSetting.(CombinationSetting); ok {
+				l.addFieldGroup(&c)
+			}
+		}
+	}
+	for _, id := range g.GetIdentities() {
+		l.addThing(id)
+	}
+	for _, r := range g.GetRanges() {
+		l.addThing(r)
+	}
+}
+
+// Sprintf pseudo-localizes the message before formatting if the picker requests PseudoLanguage,
+// as names given in a are already pseudo-localized.
+func (l errorLocalizer) Sprintf(key string, a ...any) string {
+	if l.picker.isPseudo() {
+		return fmt.Sprintf(pseudoLocalize(key, true), a...)
+	}
+	return l.printer.Sprintf(key, a...)
+}
+
+func (l errorLocalizer) Name(codeName string) string {
+	label, ok := l.labels[codeName]
+	if !ok {
+		return codeName
+	}
+	return label.GetValue(l.picker, codeName)
+}
+
+// LocalizeError renders err for users of the language picked by p, see ErrorLocalizer.
+// Errors that wrap a seederrors.LocalizedError are rendered as the wrapped error;
+// other errors are rendered by their Error method.
+func LocalizeError(err error, p *Picker, d DomainGetter) string {
+	if err == nil {
+		return ""
+	}
+	var le seederrors.LocalizedError
+	if !errors.As(err, &le) {
+		return err.Error()
+	}
+	return le.Localize(ErrorLocalizer(p, d))
+}
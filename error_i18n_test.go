@@ -0,0 +1,68 @@
+package seed_test
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xiegeo/must"
+	"golang.org/x/text/language"
+
+	. "github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+func TestLocalizeError(t *testing.T) {
+	en := NewPicker([]language.Tag{language.English}, nil)
+	zh := NewPicker([]language.Tag{language.SimplifiedChinese}, nil)
+	fr := NewPicker([]language.Tag{language.French}, nil)
+	d := must.V(NewDomain(Thing{Name: "shop"}, &Object{
+		Thing: Thing{Name: "order", Label: I18n[string]{language.English: "Order", language.Chinese: "订单"}},
+		FieldGroup: FieldGroup{
+			Fields: must.V(NewFields(&Field{
+				Thing:            Thing{Name: "quantity", Label: I18n[string]{language.English: "Quantity", language.Chinese: "数量"}},
+				FieldType:        Integer,
+				FieldTypeSetting: IntegerSetting{Min: big.NewInt(1), Max: big.NewInt(100)},
+			})),
+		},
+	}))
+
+	valueErr := d.Objects.Values()[0].Fields.Values()[0].CheckValue(int64(0))
+	tests := []struct {
+		name string
+		err  error
+		p    *Picker
+		d    DomainGetter
+		want string
+	}{
+		{"nil", nil, zh, d, ""},
+		{"plain", fmt.Errorf("oops"), zh, d, "oops"},
+		{"no domain", seederrors.NewValueRequiredError("quantity"), en, nil, `field "quantity" is required`},
+		{"en label", seederrors.NewValueRequiredError("quantity"), en, d, `field "Quantity" is required`},
+		{"zh label", seederrors.NewValueRequiredError("quantity"), zh, d, `字段“数量”是必填的`},
+		{"fallback to English", seederrors.NewObjectNotFoundError("order"), fr, d, `object "order" is not found`},
+		{"zh rule", seederrors.NewNameNotAllowedError("_a", seederrors.NameUnderline), zh, d,
+			`代码名称“_a”不被允许：不能以“_”开头或结尾，也不能有连续的“_”`},
+		{"zh values", valueErr, zh, d, `1 个值错误：“数量”处的值“0”无效：值比允许的小`},
+		{"zh wrapped", seederrors.WithMessagef(seederrors.NewObjectNotFoundError("order"), "loading"), zh, d, `找不到对象“订单”`},
+		{"zh thing type", seederrors.NewCodeNameExistsError[string, string]("shop", seederrors.ThingTypeDomain), zh, d,
+			`名称“shop”在领域中已存在`},
+		{"zh field type", seederrors.NewCodeNameExistsError("quantity", seederrors.ThingTypeField, "order"), zh, d,
+			`名称“quantity”在“订单”的字段中已存在`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, LocalizeError(tt.err, tt.p, tt.d))
+		})
+	}
+	assert.Equal(t, valueErr.Error(), LocalizeError(valueErr, nil, nil), "same as Error in English without a domain")
+}
+
+func TestCodeNameExistsErrorType(t *testing.T) {
+	for _, tt := range []seederrors.ThingType{seederrors.ThingTypeDomain, seederrors.ThingTypeObject, seederrors.ThingTypeField} {
+		err := seederrors.NewCodeNameExistsError("quantity", tt, "order")
+		assert.Equal(t, tt, err.Type)
+		assert.Equal(t, fmt.Sprintf(`name "quantity" in "%ss" of "order" already exists`, tt), err.Error())
+	}
+}
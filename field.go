@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"sort"
 	"time"
 
+	"github.com/shopspring/decimal"
+
 	"github.com/xiegeo/seed/seederrors"
 )
 
@@ -13,8 +16,63 @@ type Field struct {
 	Thing
 	FieldTypeSetting
 	FieldType
-	IsI18n   bool // if true, different values for different locals is possible. Only String and Binary need to be supported.
-	Nullable bool // if true, difference between null and zero values are significate.
+	IsI18n   bool     // if true, different values for different locals is possible. Only String and Binary need to be supported.
+	Nullable bool     // if true, difference between null and zero values are significate.
+	Default  *Default // optional value used when a value is not provided.
+}
+
+// Default describes the value of a field when a value is not provided.
+// Only one of Value and Generator should be set. Defaults are supported by orderable and enumeration fields.
+// A nullable field only uses the default if the value is missing, an explicit null is kept as is.
+type Default struct {
+	Value     any // a literal value, of the same type as other values of the field.
+	Generator DefaultGenerator
+}
+
+// DefaultGenerator generates a new default value each time it is used.
+//
+// Databases may not be able to generate every value, such as the current time at a scale of minutes.
+// Such defaults are applied to values inserted through seed, but not to rows inserted by other clients.
+type DefaultGenerator uint8
+
+const (
+	GeneratorUnset      DefaultGenerator = iota
+	GenerateNow                          // the current time, only for TimeStamp fields.
+	DefaultGeneratorMax = GenerateNow
+)
+
+var _defaultGeneratorStringer = []string{"GeneratorUnset", "Now"}
+
+func (g DefaultGenerator) String() string {
+	if int(g) >= len(_defaultGeneratorStringer) {
+		return fmt.Sprintf("DefaultGenerator(%d)", g)
+	}
+	return _defaultGeneratorStringer[g]
+}
+
+// DefaultValue returns the default value of f at time now, or false if f has no default.
+// Generated time stamps are truncated to the field's scale, in UTC unless the field supports time zone offsets.
+func (f *Field) DefaultValue(now time.Time) (any, bool) {
+	if f.Default == nil {
+		return nil, false
+	}
+	switch f.Default.Generator {
+	case GeneratorUnset:
+		return f.Default.Value, true
+	case GenerateNow:
+		s, ok := f.FieldTypeSetting.(TimeStampSetting)
+		if !ok {
+			return nil, false
+		}
+		if !s.WithTimeZoneOffset {
+			now = now.UTC()
+		}
+		if s.Scale > 0 {
+			now = now.Truncate(s.Scale)
+		}
+		return now, true
+	}
+	return nil, false
 }
 
 type FieldType int8
@@ -30,10 +88,11 @@ const (
 	Reference
 	List
 	Combination
-	FieldTypeMax = Combination
+	Enumeration
+	FieldTypeMax = Enumeration
 )
 
-var _fieldTypeStringer = []string{"FieldTypeUnset", "String", "Binary", "Boolean", "TimeStamp", "Integer", "Real", "Reference", "List", "Combination"}
+var _fieldTypeStringer = []string{"FieldTypeUnset", "String", "Binary", "Boolean", "TimeStamp", "Integer", "Real", "Reference", "List", "Combination", "Enumeration"}
 
 func (f FieldType) String() string {
 	if f < 0 || f > FieldTypeMax {
@@ -49,6 +108,16 @@ func (f FieldType) Valid() bool {
 	return true
 }
 
+// IsOrderable returns true if values of this type have a natural total order,
+// so they can be used by directional operators and ranges.
+func (f FieldType) IsOrderable() bool {
+	switch f {
+	case String, Binary, Boolean, TimeStamp, Integer, Real:
+		return true
+	}
+	return false
+}
+
 /*
 FieldTypeSetting is any of:
 
@@ -61,6 +130,7 @@ FieldTypeSetting is any of:
 		Reference   ReferenceSetting
 		List        ListSetting
 		Combination CombinationSetting
+		Enumeration EnumerationSetting
 */
 type FieldTypeSetting any
 
@@ -96,12 +166,28 @@ func FieldTypeSettingCover(s, s2 FieldTypeSetting) bool {
 	case ListSetting:
 		return vt.Covers(peek[ListSetting](s2))
 	case CombinationSetting:
-		return false
-		// return vt.Covers(peek[CombinationSetting](s2)) // future
+		return vt.Covers(peek[CombinationSetting](s2))
+	case EnumerationSetting:
+		return vt.Covers(peek[EnumerationSetting](s2))
 	}
 	return invalid()
 }
 
+// Covers returns true if f can support all values in f2.
+// Labels and descriptions are ignored.
+func (f *Field) Covers(f2 *Field) bool {
+	switch {
+	case
+		f.FieldType != f2.FieldType,
+		!settingMatchFieldType(f.FieldType, f.FieldTypeSetting),
+		!settingMatchFieldType(f2.FieldType, f2.FieldTypeSetting),
+		!f.Nullable && f2.Nullable,
+		!f.IsI18n && f2.IsI18n:
+		return false
+	}
+	return FieldTypeSettingCover(f.FieldTypeSetting, f2.FieldTypeSetting)
+}
+
 type StringSetting struct {
 	MinCodePoints int64
 	MaxCodePoints int64
@@ -176,25 +262,36 @@ func Int64Setting() IntegerSetting {
 	}
 }
 
-// Covers returns true if s can support all values in s2
+// Covers returns true if s can support all values in s2, converted to the unit of s if the units differ.
 func (s IntegerSetting) Covers(s2 IntegerSetting) bool {
+	if !s.Unit.Covers(s2.Unit) {
+		return s.coversConverted(s2)
+	}
 	switch {
 	case
 		s.Min.Cmp(s2.Min) == 1,
 		s.Max.Cmp(s2.Max) == -1:
 		return false
 	}
-	return s.Unit.Covers(s2.Unit)
+	return true
 }
 
 type Unit struct {
 	Thing
 	Symble string // a display symbol, such as: %, °C
+
+	// Units of the same Dimension, such as mass or temperature, can be converted through the base
+	// unit of the dimension: base value = value * Factor + Offset. See ConvertUnit and StandardUnits.
+	// Units without a Dimension can not be converted.
+	Dimension CodeName `json:",omitempty"`
+	Factor    *big.Rat `json:",omitempty"` // must be positive, 1 if not set
+	Offset    *big.Rat `json:",omitempty"` // 0 if not set
 }
 
-// Covers returns true if s can support all values in s2.
+// Covers returns true if s can support all values in s2 without conversion.
 //
 // For unit comparisons, no value ranges are checked, So *Unit.Covers has a different meaning.
+// Settings with units, such as IntegerSetting, also cover convertible units if ranges still fit.
 // For most use cases, receiver s should be nil, since it describes the ability to handle a set
 // of values without care to the unit. But in case it does care about the unit, we protect against
 // mixing units by returning false if s2 is different from s.
@@ -218,6 +315,10 @@ type RealSetting struct {
 
 	// Alternative settings for RealStandard of Float32 or Float64 to replace *Mantissa and *Exponent.
 	// The full range is supported if not set.
+	//
+	// For RealStandard of Decimal32 or Decimal64, *Mantissa and *Exponent describes the coefficient
+	// and exponent (value = mantissa * 10^exponent), and are set to the full range if not set.
+	// Values are exact decimals, represented by decimal.Decimal from github.com/shopspring/decimal.
 	MinFloat *float64
 	MaxFloat *float64
 
@@ -243,6 +344,85 @@ func (s *RealSetting) Valid() bool {
 		if s.MaxFloat == nil {
 			s.MaxFloat = valuePointer(math.Inf(1))
 		}
+	case Decimal32, Decimal64:
+		full := _decimalFullRange[s.Standard]
+		if s.Base == 0 {
+			s.Base = 10
+		}
+		if s.MinMantissa == nil {
+			s.MinMantissa = new(big.Int).Neg(full.maxMantissa())
+		}
+		if s.MaxMantissa == nil {
+			s.MaxMantissa = full.maxMantissa()
+		}
+		if s.MinExponent == nil {
+			s.MinExponent = valuePointer(full.minExponent)
+		}
+		if s.MaxExponent == nil {
+			s.MaxExponent = valuePointer(full.maxExponent)
+		}
+		if s.Base != 10 || !full.covers(s) {
+			return invalid()
+		}
+	default:
+		return invalid()
+	}
+	return true
+}
+
+// FitsDecimal returns true if d can be represented exactly by a decimal setting,
+// s must be valid.
+func (s RealSetting) FitsDecimal(d decimal.Decimal) bool {
+	coefficient := d.Coefficient()
+	exponent := int64(d.Exponent())
+	ten := big.NewInt(10)
+	remainder := new(big.Int)
+	for coefficient.Sign() != 0 {
+		// remove trailing zeros, so the smallest coefficient is checked
+		quotient, _ := new(big.Int).QuoRem(coefficient, ten, remainder)
+		if remainder.Sign() != 0 {
+			break
+		}
+		coefficient = quotient
+		exponent++
+	}
+	for coefficient.Sign() != 0 && exponent > *s.MaxExponent {
+		coefficient.Mul(coefficient, ten)
+		exponent--
+	}
+	if coefficient.Sign() == 0 {
+		return true
+	}
+	return exponent >= *s.MinExponent && exponent <= *s.MaxExponent &&
+		coefficient.Cmp(s.MinMantissa) >= 0 && coefficient.Cmp(s.MaxMantissa) <= 0
+}
+
+// decimalRange describes the full range of IEEE 754 decimal formats.
+type decimalRange struct {
+	digits      int64
+	minExponent int64
+	maxExponent int64
+}
+
+var _decimalFullRange = map[RealStandard]decimalRange{
+	Decimal32: {digits: 7, minExponent: -101, maxExponent: 90},
+	Decimal64: {digits: 16, minExponent: -398, maxExponent: 369},
+}
+
+func (r decimalRange) maxMantissa() *big.Int {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(r.digits), nil)
+	return max.Sub(max, big.NewInt(1))
+}
+
+func (r decimalRange) covers(s *RealSetting) bool {
+	max := r.maxMantissa()
+	switch {
+	case
+		s.MaxMantissa.Cmp(max) == 1,
+		s.MinMantissa.CmpAbs(max) == 1,
+		*s.MinExponent < r.minExponent,
+		*s.MaxExponent > r.maxExponent:
+		return false
 	}
 	return true
 }
@@ -257,16 +437,17 @@ func valuePointer[T any](v T) *T {
 	return &v
 }
 
-// Covers returns true if s can support all values in s2.
+// Covers returns true if s can support all values in s2, converted to the unit of s if the units differ.
 func (s RealSetting) Covers(s2 RealSetting) bool {
 	switch {
 	case
 		!s.Valid(), !s2.Valid(),
-		!s.Unit.Covers(s2.Unit),
 		!s.Standard.Covers(s2.Standard):
 		return false
+	case !s.Unit.Covers(s2.Unit):
+		return s.coversConverted(s2)
 	}
-	if s.Standard == CustomReal && s2.Standard == CustomReal {
+	if s.Standard.usesMantissa() && s2.Standard.usesMantissa() {
 		switch {
 		case
 			s.MinMantissa.Cmp(s2.MinMantissa) == 1,
@@ -294,19 +475,30 @@ const (
 	CustomReal RealStandard = iota
 	Float32
 	Float64
-	// Decimal32
-	// Decimal64
+	Decimal32
+	Decimal64
+	RealStandardMax = Decimal64
 )
 
-var _realStandardStringer = []string{"CustomReal", "Float32", "Float64"}
+var _realStandardStringer = []string{"CustomReal", "Float32", "Float64", "Decimal32", "Decimal64"}
 
 func (s RealStandard) String() string {
-	if s < 0 || s > Float64 {
-		return fmt.Sprintf("RealStandard(%d) out of range[%d,%d]", s, CustomReal, Float64)
+	if s < 0 || s > RealStandardMax {
+		return fmt.Sprintf("RealStandard(%d) out of range[%d,%d]", s, CustomReal, RealStandardMax)
 	}
 	return _realStandardStringer[s]
 }
 
+// IsDecimal returns true for decimal standards, which hold exact base 10 values.
+func (s RealStandard) IsDecimal() bool {
+	return s == Decimal32 || s == Decimal64
+}
+
+// usesMantissa returns true if the range of values is described by *Mantissa and *Exponent settings.
+func (s RealStandard) usesMantissa() bool {
+	return s == CustomReal || s.IsDecimal()
+}
+
 // Covers returns true if s can support all values in s2.
 func (s RealStandard) Covers(s2 RealStandard) bool {
 	switch {
@@ -314,8 +506,8 @@ func (s RealStandard) Covers(s2 RealStandard) bool {
 		s == s2,
 		// s == CustomReal, // future: allow custom type to support standard real types
 		// s2 == s. // future: allow standard real types to support custom types
-		s == Float64 && s2 == Float32:
-		// s == Decimal64 && s2 == Decimal32:
+		s == Float64 && s2 == Float32,
+		s == Decimal64 && s2 == Decimal32:
 		return true
 	}
 	return false
@@ -330,9 +522,17 @@ type ReferenceSetting struct {
 }
 
 // Covers returns true if s can support all values in s2.
+//
+// Both must refer to the same object and identity. PromotionMap is not checked, because promoted
+// fields do not change the values of the reference.
 func (s ReferenceSetting) Covers(s2 ReferenceSetting) bool {
-	return false // not sure if there is a use case yet
-	// return s.Target == "" || s.Target == s2.Target
+	switch {
+	case
+		s.Object != s2.Object,
+		s.Identity != s2.Identity:
+		return false
+	}
+	return s.ReferenceTrackingOption.Covers(s2.ReferenceTrackingOption)
 }
 
 type ReferenceField struct {
@@ -345,6 +545,11 @@ type ReferenceTrackingOption struct {
 	OnDelete ReferenceTrackingAction
 }
 
+// Covers returns true if references tracked by o can hold all references tracked by o2.
+func (o ReferenceTrackingOption) Covers(o2 ReferenceTrackingOption) bool {
+	return o.OnUpdate.Covers(o2.OnUpdate) && o.OnDelete.Covers(o2.OnDelete)
+}
+
 type ReferenceTrackingAction uint8
 
 const (
@@ -354,6 +559,22 @@ const (
 	ActionIgnore                                  // Nothing happens, the reference is only a suggestion.
 )
 
+var _referenceTrackingActionStringer = []string{"ActionRestrict", "ActionCascade", "ActionSetNull", "ActionIgnore"}
+
+// Covers returns true if references tracked by a can hold all references tracked by a2.
+// All actions except ActionIgnore keep references pointing to existing targets, so only
+// ActionIgnore can hold references left dangling by ActionIgnore.
+func (a ReferenceTrackingAction) Covers(a2 ReferenceTrackingAction) bool {
+	return a == ActionIgnore || a2 != ActionIgnore
+}
+
+func (a ReferenceTrackingAction) String() string {
+	if a > ActionIgnore {
+		return fmt.Sprintf("ReferenceTrackingAction(%d) out of range[%d,%d]", a, ActionRestrict, ActionIgnore)
+	}
+	return _referenceTrackingActionStringer[a]
+}
+
 // ListSetting describes a collection of the same type:
 //
 //	| IsOrdered | IsUnique | collection type |
@@ -397,3 +618,51 @@ func (s ListSetting) ItemField(parent *Field) *Field {
 }
 
 type CombinationSetting = FieldGroup // Reuse FieldGroup
+
+// EnumerationSetting describes a closed list of allowed values, such as status or category.
+// Values are CodeNames, and are displayed by their labels.
+type EnumerationSetting struct {
+	Values []EnumerationValue
+}
+
+// EnumerationValue is an allowed value of an enumeration.
+type EnumerationValue struct {
+	Thing
+	Order int64 // optional display order, values of the same order are displayed by their listed order.
+}
+
+// Covers returns true if s can support all values in s2, so adding values widens an enumeration.
+// Labels and orders are ignored.
+func (s EnumerationSetting) Covers(s2 EnumerationSetting) bool {
+	for _, v := range s2.Values {
+		if !s.Has(v.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+// Has returns true if cn is an allowed value.
+func (s EnumerationSetting) Has(cn CodeName) bool {
+	_, ok := s.Get(cn)
+	return ok
+}
+
+// Get returns the value by name.
+func (s EnumerationSetting) Get(cn CodeName) (EnumerationValue, bool) {
+	for _, v := range s.Values {
+		if v.Name == cn {
+			return v, true
+		}
+	}
+	return EnumerationValue{}, false
+}
+
+// Sorted returns the values in display order.
+func (s EnumerationSetting) Sorted() []EnumerationValue {
+	out := append([]EnumerationValue(nil), s.Values...)
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Order < out[j].Order
+	})
+	return out
+}
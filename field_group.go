@@ -2,6 +2,7 @@ package seed
 
 import (
 	"github.com/xiegeo/seed/dictionary"
+	"github.com/xiegeo/seed/seederrors"
 )
 
 // Object describes a business object.
@@ -40,6 +41,78 @@ func NewFields0[T ThingGetter]() *dictionary.SelfKeyed[CodeName, T] {
 	)
 }
 
+// Covers returns true if g can support all values in g2, when used as a CombinationSetting.
+//
+//   - Both must have the same field names, and each field in g covers its counterpart in g2.
+//   - Each identity in g must be implied by an identity in g2, by being a super set of it.
+//   - Each range in g must be in g2, and g can only exclude end values if g2 also does.
+func (g FieldGroup) Covers(g2 FieldGroup) bool {
+	if g.Fields == nil || g2.Fields == nil {
+		return invalid()
+	}
+	if g.Fields.Count() != g2.Fields.Count() {
+		return false
+	}
+	for _, f2 := range g2.Fields.Values() {
+		f, ok := g.Fields.Get(f2.Name)
+		if !ok || !f.Covers(f2) {
+			return false
+		}
+	}
+	for _, id := range g.Identities {
+		if !identityImplied(id, g2.Identities) {
+			return false
+		}
+	}
+	return RangeRanges(&g, func(r Range) error {
+		found := false
+		_ = RangeRanges(&g2, func(r2 Range) error {
+			if r.Start == r2.Start && r.End == r2.End && (r.IncludeEndValue || !r2.IncludeEndValue) {
+				found = true
+			}
+			return nil
+		})
+		if !found {
+			return errNotCovered
+		}
+		return nil
+	}) == nil
+}
+
+// errNotCovered is used to stop RangeRanges early.
+var errNotCovered = seederrors.NewSystemError("not covered")
+
+// identityImplied returns true if any identity in ids is a subset of id, so uniqueness of id is implied.
+func identityImplied(id Identity, ids []Identity) bool {
+	fields := identityFields(id)
+	for _, id2 := range ids {
+		implied := true
+		for cn := range identityFields(id2) {
+			if _, ok := fields[cn]; !ok {
+				implied = false
+				break
+			}
+		}
+		if implied {
+			return true
+		}
+	}
+	return false
+}
+
+// identityFields returns the set of field names used by id, including start and end of ranges.
+func identityFields(id Identity) map[CodeName]struct{} {
+	out := make(map[CodeName]struct{}, len(id.Fields)+len(id.Ranges)*2)
+	for _, cn := range id.Fields {
+		out[cn] = struct{}{}
+	}
+	for _, r := range id.Ranges {
+		out[r.Start] = struct{}{}
+		out[r.End] = struct{}{}
+	}
+	return out
+}
+
 func RangeRanges(g FieldGroupGetter, f func(r Range) error) error {
 	for _, id := range g.GetIdentities() {
 		for _, r := range id.Ranges {
@@ -0,0 +1,133 @@
+package seed_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
+
+	. "github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/demo/testdomain"
+)
+
+func TestCombinationSettingCovers(t *testing.T) {
+	start := testdomain.DateTimeSec()
+	end := testdomain.DateTimeSec()
+	end.Name = "end"
+	base := func() CombinationSetting {
+		return CombinationSetting{
+			Fields: must.V(NewFields(start, end, testdomain.JSInteger())),
+		}
+	}
+	wider := base()
+	wider.Fields = must.V(wider.Fields.NewMap(func(f *Field) (*Field, error) {
+		if f.Name == testdomain.JSInteger().Name {
+			return testdomain.Integer64(), nil
+		}
+		return f, nil
+	}))
+	assert.False(t, FieldTypeSettingCover(base(), wider), "renamed field")
+	wider.Fields = must.V(NewFields(start, end, func() *Field {
+		f := testdomain.Integer64()
+		f.Name = testdomain.JSInteger().Name
+		f.Nullable = true
+		return f
+	}()))
+	assert.True(t, FieldTypeSettingCover(wider, base()))
+	assert.False(t, FieldTypeSettingCover(base(), wider))
+
+	withID := base()
+	withID.Identities = []Identity{{Fields: []CodeName{start.Name}}}
+	withLongerID := base()
+	withLongerID.Identities = []Identity{{Fields: []CodeName{start.Name, end.Name}}}
+	assert.True(t, FieldTypeSettingCover(base(), withID), "no identity allows more values")
+	assert.False(t, FieldTypeSettingCover(withID, base()))
+	assert.True(t, FieldTypeSettingCover(withLongerID, withID), "unique start implies unique start and end")
+	assert.False(t, FieldTypeSettingCover(withID, withLongerID))
+
+	withRange := base()
+	withRange.Ranges = []Range{{Start: start.Name, End: end.Name, IncludeEndValue: true}}
+	withStrictRange := base()
+	withStrictRange.Ranges = []Range{{Start: start.Name, End: end.Name}}
+	assert.True(t, FieldTypeSettingCover(base(), withRange))
+	assert.False(t, FieldTypeSettingCover(withRange, base()))
+	assert.True(t, FieldTypeSettingCover(withRange, withStrictRange))
+	assert.False(t, FieldTypeSettingCover(withStrictRange, withRange))
+
+	assert.False(t, FieldTypeSettingCover(base(), StringSetting{}))
+}
+
+func TestReferenceSettingCovers(t *testing.T) {
+	ref := ReferenceSetting{Object: "a", Identity: "id"}
+	assert.True(t, ref.Covers(ref))
+	assert.False(t, ref.Covers(ReferenceSetting{Object: "b", Identity: "id"}))
+	assert.False(t, ref.Covers(ReferenceSetting{Object: "a"}))
+
+	cascade := ref
+	cascade.OnUpdate = ActionCascade
+	cascade.OnDelete = ActionSetNull
+	ignore := ref
+	ignore.OnDelete = ActionIgnore
+	assert.True(t, ref.Covers(cascade))
+	assert.True(t, cascade.Covers(ref))
+	assert.True(t, ignore.Covers(ref))
+	assert.False(t, ref.Covers(ignore), "can not hold dangling references")
+}
+
+func TestDecimalRealSettingCovers(t *testing.T) {
+	d32 := RealSetting{Standard: Decimal32}
+	d64 := RealSetting{Standard: Decimal64}
+	assert.True(t, d64.Covers(d32))
+	assert.False(t, d32.Covers(d64))
+	assert.False(t, d64.Covers(RealSetting{Standard: Float32}))
+
+	cents := RealSetting{Standard: Decimal64, MinExponent: valuePointer(int64(-2))}
+	assert.True(t, d64.Covers(cents))
+	assert.False(t, cents.Covers(d64))
+
+	tooLarge := RealSetting{Standard: Decimal32, MaxMantissa: big.NewInt(1e7)}
+	assert.False(t, tooLarge.Valid())
+}
+
+func TestRealSettingFitsDecimal(t *testing.T) {
+	setting := RealSetting{Standard: Decimal32}
+	require.True(t, setting.Valid())
+	for v, fits := range map[string]bool{
+		"0":        true,
+		"9999999":  true,
+		"99999990": true,
+		"12345678": false,
+		"1.000000": true,
+		"1e90":     true,
+		"1e96":     true,
+		"1e97":     false,
+		"1e-101":   true,
+		"1e-102":   false,
+	} {
+		assert.Equal(t, fits, setting.FitsDecimal(decimal.RequireFromString(v)), v)
+	}
+}
+
+func TestEnumerationSettingCovers(t *testing.T) {
+	status := testdomain.Enumeration().FieldTypeSetting.(EnumerationSetting)
+	fewer := EnumerationSetting{Values: status.Values[1:]}
+	assert.True(t, status.Covers(fewer))
+	assert.False(t, fewer.Covers(status))
+	assert.True(t, status.Covers(status))
+
+	reordered := EnumerationSetting{Values: []EnumerationValue{
+		{Thing: Thing{Name: "archived"}, Order: -1},
+		{Thing: Thing{Name: "draft"}},
+	}}
+	assert.True(t, status.Covers(reordered), "labels and orders are ignored")
+	assert.Equal(t, []CodeName{"archived", "draft"}, names(reordered.Sorted()))
+	assert.True(t, status.Has("draft"))
+	assert.False(t, status.Has("deleted"))
+}
+
+func valuePointer[T any](v T) *T {
+	return &v
+}
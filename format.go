@@ -0,0 +1,643 @@
+package seed
+
+import (
+	"encoding/base64"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/puzpuzpuz/xsync/v2"
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// FormatValue formats v, a value of f, for display to users of the language picked by p.
+//
+// Numbers are written with the digits and separators of the language, as found by golang.org/x/text,
+// followed by the symbol of the unit if there is one. Time stamps show the date, then the time to the
+// precision of TimeStampSetting.Scale, and the time zone offset if WithTimeZoneOffset is set.
+// Booleans, enumerations and the separators of lists use words of the language. Binary values use
+// standard base64. Null formats as an empty string.
+//
+// I18n values are picked by p before formatting. References and combinations are not supported.
+func FormatValue(f *Field, v any, p *Picker) (string, error) {
+	if p == nil {
+		p = _lastPicker
+	}
+	if err := checkFormatSupported(f); err != nil {
+		return "", err
+	}
+	v = derefValue(v)
+	if v == nil {
+		return "", nil
+	}
+	if f.IsI18n {
+		values, ok := i18nValues(v)
+		if !ok {
+			return "", seederrors.NewValueError(seederrors.ValueType, v, string(f.Name))
+		}
+		v = derefValue(values.GetValue(p, nil))
+		if v == nil {
+			return "", nil
+		}
+	}
+	s, err := newValueFormatter(p).format(f.FieldTypeSetting, v)
+	if err != nil {
+		return "", withValuePath(err, string(f.Name))
+	}
+	return s, nil
+}
+
+// ParseValue parses s, formatted by FormatValue or typed by users of the language picked by p,
+// as a value of f. Besides localized text, it accepts ASCII digits and minus signs, true and false,
+// RFC 3339 time stamps and code names of enumerations. An empty string is null, unless f is a
+// String field.
+//
+// Integers parse to int64, or *big.Int if they are too large; reals parse to float64 or
+// decimal.Decimal by RealSetting.Standard; lists parse to []any. Parsed values are not checked
+// against the setting of f, see Field.CheckValue. I18n fields are not supported.
+//
+// Numbers must use the decimal separator of the picked language, and group separators are only
+// accepted between its digit groups, so "1.5" and "1234.5" are not valid for German, where "."
+// separates groups of three digits.
+func ParseValue(f *Field, s string, p *Picker) (any, error) {
+	if p == nil {
+		p = _lastPicker
+	}
+	if f.IsI18n {
+		return nil, seederrors.NewFieldNotSupportedError("I18n", f.Name)
+	}
+	if err := checkFormatSupported(f); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(s) == "" && f.FieldType != String {
+		return nil, nil
+	}
+	v, err := newValueFormatter(p).parse(f.FieldTypeSetting, s)
+	if err != nil {
+		return nil, withValuePath(err, string(f.Name))
+	}
+	return v, nil
+}
+
+// checkFormatSupported returns an error if values of f, or items of lists in f, are references or
+// combinations, which are not displayed as single values.
+func checkFormatSupported(f *Field) error {
+	t, s := f.FieldType, f.FieldTypeSetting
+	for {
+		switch t {
+		case Reference, Combination:
+			return seederrors.NewFieldNotSupportedError(t.String(), f.Name)
+		case List:
+			ls, ok := s.(ListSetting)
+			if !ok {
+				return nil // reported as a value type error
+			}
+			t, s = ls.ItemType, ls.ItemTypeSetting
+		default:
+			return nil
+		}
+	}
+}
+
+// i18nValues returns v, a map from language tags to values, as an I18n.
+func i18nValues(v any) (I18n[any], bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map || rv.Type().Key() != reflect.TypeOf(language.Tag{}) {
+		return nil, false
+	}
+	out := make(I18n[any], rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		out[iter.Key().Interface().(language.Tag)] = iter.Value().Interface()
+	}
+	return out, true
+}
+
+// valueFormatter formats values in one language.
+type valueFormatter struct {
+	picker  *Picker
+	symbols numberSymbols
+}
+
+func newValueFormatter(p *Picker) valueFormatter {
+	return valueFormatter{picker: p, symbols: symbolsOf(p.Language())}
+}
+
+// word returns the word of the picked language, or the word in English, for display.
+func (vf valueFormatter) word(w I18n[string]) string {
+	return w.GetValue(vf.picker, w[language.English])
+}
+
+// syntax returns the layout or separator of the picked language, or fallback. Unlike words,
+// syntax is never pseudo-localized.
+func (vf valueFormatter) syntax(w I18n[string], fallback string) string {
+	tag, _ := Pick[string](vf.picker, w)
+	if v, ok := w[tag]; ok {
+		return v
+	}
+	return fallback
+}
+
+var (
+	_wordTrue = I18n[string]{
+		language.English: "Yes", language.Chinese: "是", language.Japanese: "はい",
+		language.German: "Ja", language.French: "Oui", language.Spanish: "Sí",
+	}
+	_wordFalse = I18n[string]{
+		language.English: "No", language.Chinese: "否", language.Japanese: "いいえ",
+		language.German: "Nein", language.French: "Non", language.Spanish: "No",
+	}
+	_listSeparator = I18n[string]{
+		language.English: ", ", language.Chinese: "、", language.Japanese: "、",
+	}
+	_dateLayout = I18n[string]{
+		language.English: "Jan 2, 2006", language.Chinese: "2006年1月2日", language.Japanese: "2006年1月2日",
+		language.German: "2.1.2006", language.French: "02/01/2006", language.Spanish: "2/1/2006",
+	}
+)
+
+const _defaultDateLayout = "2006-01-02"
+
+func (vf valueFormatter) format(s FieldTypeSetting, v any) (string, error) {
+	switch vt := s.(type) {
+	case StringSetting:
+		if str, ok := v.(string); ok {
+			return str, nil
+		}
+	case BinarySetting:
+		if b, ok := v.([]byte); ok {
+			return base64.StdEncoding.EncodeToString(b), nil
+		}
+	case BooleanSetting:
+		if b, ok := v.(bool); ok {
+			if b {
+				return vf.word(_wordTrue), nil
+			}
+			return vf.word(_wordFalse), nil
+		}
+	case TimeStampSetting:
+		if t, ok := v.(time.Time); ok {
+			if !vt.WithTimeZoneOffset {
+				t = t.UTC()
+			}
+			return t.Format(vf.timeLayout(vt)), nil
+		}
+	case IntegerSetting:
+		if i, ok := toBigInt(v); ok {
+			return vf.withUnit(vf.symbols.format(i.String()), vt.Unit), nil
+		}
+	case RealSetting:
+		switch n := v.(type) {
+		case float64:
+			return vf.withUnit(vf.symbols.format(strconv.FormatFloat(n, 'f', -1, 64)), vt.Unit), nil
+		case decimal.Decimal:
+			return vf.withUnit(vf.symbols.format(decimalString(n)), vt.Unit), nil
+		}
+	case EnumerationSetting:
+		var name CodeName
+		switch n := v.(type) {
+		case CodeName:
+			name = n
+		case string:
+			name = CodeName(n)
+		default:
+			return "", seederrors.NewValueError(seederrors.ValueType, v)
+		}
+		ev, ok := vt.Get(name)
+		if !ok {
+			return "", seederrors.NewValueError(seederrors.ValueNotAllowed, v)
+		}
+		return ev.Label.GetValue(vf.picker, string(ev.Name)), nil
+	case ListSetting:
+		return vf.formatList(vt, v)
+	}
+	return "", seederrors.NewValueError(seederrors.ValueType, v)
+}
+
+func (vf valueFormatter) formatList(s ListSetting, v any) (string, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return "", seederrors.NewValueError(seederrors.ValueType, v)
+	}
+	items := make([]string, rv.Len())
+	for i := range items {
+		item := derefValue(rv.Index(i).Interface())
+		if item == nil {
+			return "", seederrors.NewValueError(seederrors.ValueRequired, item, strconv.Itoa(i))
+		}
+		var err error
+		items[i], err = vf.format(s.ItemTypeSetting, item)
+		if err != nil {
+			return "", withValuePath(err, strconv.Itoa(i))
+		}
+	}
+	return strings.Join(items, vf.syntax(_listSeparator, _listSeparator[language.English])), nil
+}
+
+// withValuePath prepends prefix to the path of value errors.
+func withValuePath(err error, prefix string) error {
+	if ve, ok := err.(seederrors.ValueError); ok { //nolint:errorlint // created by valueFormatter
+		return seederrors.NewValueError(ve.Rule, ve.Value, withPath([]string{prefix}, ve.Path...)...)
+	}
+	return err
+}
+
+// timeLayout returns the layout of time stamps of s: the date, the time to the precision of
+// s.Scale unless it is at least a day, and the time zone offset if used.
+func (vf valueFormatter) timeLayout(s TimeStampSetting) string {
+	layout := vf.dateLayout()
+	switch {
+	case s.Scale >= 24*time.Hour:
+	case s.Scale >= time.Minute:
+		layout += " 15:04"
+	case s.Scale >= time.Second:
+		layout += " 15:04:05"
+	default:
+		digits := 9
+		for scale := s.Scale; scale > 0 && scale%10 == 0 && digits > 0; scale /= 10 {
+			digits--
+		}
+		layout += " 15:04:05." + strings.Repeat("0", digits)
+	}
+	if s.WithTimeZoneOffset {
+		layout += " -07:00"
+	}
+	return layout
+}
+
+func (vf valueFormatter) dateLayout() string {
+	return vf.syntax(_dateLayout, _defaultDateLayout)
+}
+
+// unitSymbol returns the symbol of u, or its label if it has no symbol.
+func (vf valueFormatter) unitSymbol(u *Unit) string {
+	if u.Symble != "" {
+		return u.Symble
+	}
+	return u.Label.GetValue(vf.picker, string(u.Name))
+}
+
+func (vf valueFormatter) withUnit(s string, u *Unit) string {
+	if u == nil {
+		return s
+	}
+	symbol := vf.unitSymbol(u)
+	if symbol == "%" {
+		return s + symbol
+	}
+	return s + " " + symbol
+}
+
+func (vf valueFormatter) withoutUnit(s string, u *Unit) string {
+	s = strings.TrimSpace(s)
+	if u == nil {
+		return s
+	}
+	for _, symbol := range []string{vf.unitSymbol(u), u.Symble, string(u.Name)} {
+		if symbol != "" && strings.HasSuffix(s, symbol) {
+			return strings.TrimSpace(strings.TrimSuffix(s, symbol))
+		}
+	}
+	return s
+}
+
+func (vf valueFormatter) parse(s FieldTypeSetting, str string) (any, error) {
+	switch vt := s.(type) {
+	case StringSetting:
+		return str, nil
+	case BinarySetting:
+		b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(str))
+		if err == nil {
+			return b, nil
+		}
+	case BooleanSetting:
+		if b, ok := parseBool(strings.TrimSpace(str)); ok {
+			return b, nil
+		}
+	case TimeStampSetting:
+		return vf.parseTime(vt, str)
+	case IntegerSetting:
+		n, ok := vf.symbols.parse(vf.withoutUnit(str, vt.Unit))
+		if i, isInt := new(big.Int).SetString(n, 10); ok && isInt {
+			if i.IsInt64() {
+				return i.Int64(), nil
+			}
+			return i, nil
+		}
+	case RealSetting:
+		n, ok := vf.symbols.parse(vf.withoutUnit(str, vt.Unit))
+		if !ok {
+			break
+		}
+		if vt.Standard.IsDecimal() {
+			d, err := decimal.NewFromString(n)
+			if err == nil {
+				return d, nil
+			}
+			break
+		}
+		f, err := strconv.ParseFloat(n, 64)
+		if err == nil {
+			return f, nil
+		}
+	case EnumerationSetting:
+		if name, ok := parseEnumeration(vt, strings.TrimSpace(str)); ok {
+			return name, nil
+		}
+		return nil, seederrors.NewValueError(seederrors.ValueNotAllowed, str)
+	case ListSetting:
+		return vf.parseList(vt, str)
+	}
+	return nil, seederrors.NewValueError(seederrors.ValueType, str)
+}
+
+func (vf valueFormatter) parseList(s ListSetting, str string) (any, error) {
+	if strings.TrimSpace(str) == "" {
+		return []any{}, nil
+	}
+	parts := strings.Split(str, vf.syntax(_listSeparator, _listSeparator[language.English]))
+	out := make([]any, len(parts))
+	for i, part := range parts {
+		var err error
+		out[i], err = vf.parse(s.ItemTypeSetting, part)
+		if err != nil {
+			return nil, withValuePath(err, strconv.Itoa(i))
+		}
+	}
+	return out, nil
+}
+
+func (vf valueFormatter) parseTime(s TimeStampSetting, str string) (time.Time, error) {
+	str = strings.TrimSpace(str)
+	layouts := []string{vf.timeLayout(s)}
+	if s.Scale < 24*time.Hour {
+		layouts = append(layouts, vf.dateLayout()) // midnight
+	}
+	layouts = append(layouts, time.RFC3339Nano, _defaultDateLayout)
+	for _, layout := range layouts {
+		t, err := time.ParseInLocation(layout, str, time.UTC)
+		if err != nil {
+			continue
+		}
+		if !s.WithTimeZoneOffset {
+			t = t.UTC()
+		}
+		return t, nil
+	}
+	return time.Time{}, seederrors.NewValueError(seederrors.ValueType, str)
+}
+
+// matchesWord returns true if s is w ignoring case, or w pseudo-localized, see PseudoLanguage.
+func matchesWord(s, w string) bool {
+	return strings.EqualFold(s, w) || s == pseudoLocalize(w, false)
+}
+
+// parseBool accepts the boolean words of all languages, ignoring case, and their pseudo forms.
+func parseBool(s string) (bool, bool) {
+	for b, words := range map[bool]I18n[string]{true: _wordTrue, false: _wordFalse} {
+		if strings.EqualFold(s, strconv.FormatBool(b)) {
+			return b, true
+		}
+		for _, w := range words {
+			if matchesWord(s, w) {
+				return b, true
+			}
+		}
+	}
+	return false, false
+}
+
+// parseEnumeration finds the value by code name, or by a label of any language, ignoring case,
+// or by their pseudo forms.
+func parseEnumeration(s EnumerationSetting, str string) (CodeName, bool) {
+	for _, v := range s.Values {
+		if matchesWord(str, string(v.Name)) {
+			return v.Name, true
+		}
+		for _, label := range v.Label {
+			if matchesWord(str, label) {
+				return v.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// numberSymbols are the digits, separators and grouping used to write decimal numbers in a language.
+type numberSymbols struct {
+	digits             [10]rune
+	minus              string
+	decimal, group     string
+	primary, secondary int // sizes of the last digit group, and other groups of the integer part
+}
+
+var _rootSymbols = numberSymbols{
+	digits: [10]rune{'0', '1', '2', '3', '4', '5', '6', '7', '8', '9'},
+	minus:  "-", decimal: ".", group: ",", primary: 3, secondary: 3,
+}
+
+var _numberSymbols = xsync.NewMapOf[numberSymbols]()
+
+// symbolsOf returns the number symbols of tag, found from numbers formatted by golang.org/x/text.
+func symbolsOf(tag language.Tag) numberSymbols {
+	key := tag.String()
+	if sym, ok := _numberSymbols.Load(key); ok {
+		return sym
+	}
+	sym, ok := findSymbols(message.NewPrinter(tag))
+	if !ok {
+		sym = _rootSymbols
+	}
+	_numberSymbols.Store(key, sym)
+	return sym
+}
+
+func findSymbols(p *message.Printer) (numberSymbols, bool) {
+	sym := numberSymbols{}
+	for i := range sym.digits {
+		r, size := utf8.DecodeRuneInString(p.Sprint(number.Decimal(i)))
+		if size == 0 {
+			return sym, false
+		}
+		sym.digits[i] = r
+	}
+	minus := sym.toASCII(p.Sprint(number.Decimal(-1)))
+	if !strings.HasSuffix(minus, "1") {
+		return sym, false
+	}
+	sym.minus = strings.TrimSuffix(minus, "1")
+	sample := sym.toASCII(p.Sprint(number.Decimal(1234567.5)))
+	return sym.fromSample(sample)
+}
+
+// fromSample reads the separators and grouping from sample, which is 1234567.5 with ASCII digits.
+func (sym numberSymbols) fromSample(sample string) (numberSymbols, bool) {
+	var groups, separators []string
+	for len(sample) > 0 {
+		i := strings.IndexFunc(sample, func(r rune) bool { return r < '0' || r > '9' })
+		if i < 0 {
+			i = len(sample)
+		}
+		groups = append(groups, sample[:i])
+		sample = sample[i:]
+		j := strings.IndexFunc(sample, func(r rune) bool { return r >= '0' && r <= '9' })
+		if j < 0 {
+			j = len(sample)
+		}
+		if j > 0 {
+			separators = append(separators, sample[:j])
+		}
+		sample = sample[j:]
+	}
+	if len(groups) < 3 || len(separators) != len(groups)-1 || groups[len(groups)-1] != "5" {
+		return sym, false
+	}
+	sym.decimal = separators[len(separators)-1]
+	integer := groups[:len(groups)-1]
+	sym.group = separators[0]
+	sym.primary = len(integer[len(integer)-1])
+	sym.secondary = sym.primary
+	if len(integer) > 2 {
+		sym.secondary = len(integer[len(integer)-2])
+	}
+	return sym, sym.primary > 0 && sym.secondary > 0
+}
+
+// toASCII replaces the digits of sym with ASCII digits.
+func (sym numberSymbols) toASCII(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		for d, digit := range sym.digits {
+			if r == digit {
+				r = rune('0' + d)
+				break
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// format writes n, a plain decimal number such as -1234.5, with the symbols of sym.
+func (sym numberSymbols) format(n string) string {
+	if n == "NaN" || strings.HasSuffix(n, "Inf") {
+		return n
+	}
+	var b strings.Builder
+	if strings.HasPrefix(n, "-") {
+		b.WriteString(sym.minus)
+		n = n[1:]
+	}
+	integer, fraction, hasFraction := strings.Cut(n, ".")
+	size := sym.primary
+	var groups []string
+	for len(integer) > size {
+		groups = append(groups, integer[len(integer)-size:])
+		integer = integer[:len(integer)-size]
+		size = sym.secondary
+	}
+	groups = append(groups, integer)
+	for i := len(groups) - 1; i >= 0; i-- {
+		sym.writeDigits(&b, groups[i])
+		if i > 0 {
+			b.WriteString(sym.group)
+		}
+	}
+	if hasFraction {
+		b.WriteString(sym.decimal)
+		sym.writeDigits(&b, fraction)
+	}
+	return b.String()
+}
+
+func (sym numberSymbols) writeDigits(b *strings.Builder, digits string) {
+	for _, r := range digits {
+		b.WriteRune(sym.digits[r-'0'])
+	}
+}
+
+// ungroup removes group separators from the integer part of a number. Separators are only
+// accepted between digit groups of the sizes used by format, so that a decimal point of another
+// locale, such as "1.5" in German, is not read as a group separator.
+func (sym numberSymbols) ungroup(integer string) (string, bool) {
+	if sym.group == "" || !strings.Contains(integer, sym.group) {
+		return integer, true
+	}
+	if sym.primary <= 0 {
+		return "", false
+	}
+	groups := strings.Split(integer, sym.group)
+	last := len(groups) - 1
+	for i, g := range groups {
+		switch {
+		case i == last:
+			if len(g) != sym.primary {
+				return "", false
+			}
+		case i == 0:
+			if len(g) == 0 || len(g) > sym.secondary {
+				return "", false
+			}
+		case len(g) != sym.secondary:
+			return "", false
+		}
+	}
+	return strings.Join(groups, ""), true
+}
+
+// parse returns s, a number written with the symbols of sym or in plain ASCII,
+// as a plain decimal number.
+func (sym numberSymbols) parse(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	negative := false
+	for _, minus := range []string{sym.minus, "-", "−"} {
+		if minus != "" && strings.HasPrefix(s, minus) {
+			negative = true
+			s = strings.TrimSpace(strings.TrimPrefix(s, minus))
+			break
+		}
+	}
+	s = sym.toASCII(s)
+	if strings.TrimSpace(sym.group) == "" {
+		s = strings.Map(func(r rune) rune {
+			if r == ' ' || r == '\u00a0' || r == '\u202f' {
+				return ' '
+			}
+			return r
+		}, s)
+		s = strings.ReplaceAll(s, " ", sym.group)
+	}
+	integer, fraction, hasDecimal := strings.Cut(s, sym.decimal)
+	integer, ok := sym.ungroup(integer)
+	if !ok {
+		return "", false
+	}
+	s = integer
+	if hasDecimal {
+		s += "." + fraction
+	}
+	if s == "" || s == "." {
+		return "", false
+	}
+	seenPoint := false
+	for _, r := range s {
+		switch {
+		case r == '.' && !seenPoint:
+			seenPoint = true
+		case r < '0' || r > '9':
+			return "", false
+		}
+	}
+	if negative {
+		s = "-" + s
+	}
+	return s, true
+}
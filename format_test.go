@@ -0,0 +1,200 @@
+package seed_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
+	"golang.org/x/text/language"
+
+	. "github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+func TestFormatValue(t *testing.T) {
+	en := NewPicker([]language.Tag{language.English}, nil)
+	de := NewPicker([]language.Tag{language.German}, nil)
+	fr := NewPicker([]language.Tag{language.French}, nil)
+	zh := NewPicker([]language.Tag{language.Chinese}, nil)
+	hi := NewPicker([]language.Tag{language.Hindi}, nil)
+	ar := NewPicker([]language.Tag{language.Arabic}, nil)
+
+	integer := &Field{Thing: Thing{Name: "count"}, FieldType: Integer, FieldTypeSetting: Int64Setting()}
+	weight := &Field{Thing: Thing{Name: "weight"}, FieldType: Real, FieldTypeSetting: RealSetting{
+		Standard: Decimal64, Unit: standardUnit(t, "kilogram"),
+	}}
+	length := &Field{Thing: Thing{Name: "length"}, FieldType: Real, FieldTypeSetting: RealSetting{
+		Standard: Float64, Unit: &Unit{Thing: Thing{Name: "meter", Label: I18n[string]{language.English: "meters", language.Chinese: "米"}}},
+	}}
+	ratio := &Field{Thing: Thing{Name: "ratio"}, FieldType: Integer, FieldTypeSetting: IntegerSetting{
+		Min: big.NewInt(0), Max: big.NewInt(100), Unit: &Unit{Thing: Thing{Name: "percent"}, Symble: "%"},
+	}}
+	large, _ := new(big.Int).SetString("-12345678901234567890", 10)
+	flag := &Field{Thing: Thing{Name: "flag"}, FieldType: Boolean, FieldTypeSetting: BooleanSetting{}, Nullable: true}
+
+	tests := []struct {
+		name   string
+		f      *Field
+		v      any
+		p      *Picker
+		want   string
+		parsed any
+	}{
+		{"en int", integer, int64(-1234567), en, "-1,234,567", int64(-1234567)},
+		{"de int", integer, 1234567, de, "1.234.567", int64(1234567)},
+		{"fr int", integer, int64(1234567), fr, "1\u00a0234\u00a0567", int64(1234567)},
+		{"hi int", integer, int64(1234567), hi, "12,34,567", int64(1234567)},
+		{"ar int", integer, int64(12), ar, "١٢", int64(12)},
+		{"big int", integer, large, en, "-12,345,678,901,234,567,890", large},
+		{"en decimal", weight, must.V(decimal.NewFromString("1234.50")), en, "1,234.50 kg", must.V(decimal.NewFromString("1234.50"))},
+		{"de decimal", weight, must.V(decimal.NewFromString("1234.50")), de, "1.234,50 kg", must.V(decimal.NewFromString("1234.50"))},
+		{"float label", length, 0.25, zh, "0.25 米", 0.25},
+		{"percent", ratio, int64(50), en, "50%", int64(50)},
+		{"en true", flag, true, en, "Yes", true},
+		{"zh false", flag, false, zh, "否", false},
+		{"null", flag, nil, en, "", nil},
+		{"null pointer", flag, (*bool)(nil), en, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatValue(tt.f, tt.v, tt.p)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			parsed, err := ParseValue(tt.f, got, tt.p)
+			require.NoError(t, err)
+			assert.Equal(t, tt.parsed, parsed)
+		})
+	}
+
+	assert.Equal(t, int64(-1234), must.V(ParseValue(integer, "-1234", de)), "plain numbers")
+	assert.Equal(t, true, must.V(ParseValue(flag, "true", zh)))
+	assert.Equal(t, false, must.V(ParseValue(flag, "nein", en)), "words of any language")
+	_, err := ParseValue(integer, "1.5", en)
+	assert.ErrorContains(t, err, string(seederrors.ValueType))
+	_, err = ParseValue(flag, "maybe", en)
+	assert.ErrorAs(t, err, &seederrors.ValueError{})
+	_, err = FormatValue(integer, "1", en)
+	assert.ErrorContains(t, err, string(seederrors.ValueType))
+}
+
+func TestParseValueGroupSeparators(t *testing.T) {
+	en := NewPicker([]language.Tag{language.English}, nil)
+	de := NewPicker([]language.Tag{language.German}, nil)
+	fr := NewPicker([]language.Tag{language.French}, nil)
+	hi := NewPicker([]language.Tag{language.Hindi}, nil)
+	integer := &Field{Thing: Thing{Name: "count"}, FieldType: Integer, FieldTypeSetting: Int64Setting()}
+	amount := &Field{Thing: Thing{Name: "amount"}, FieldType: Real, FieldTypeSetting: RealSetting{Standard: Float64}}
+
+	tests := []struct {
+		name string
+		f    *Field
+		s    string
+		p    *Picker
+		want any // nil if not valid
+	}{
+		{"de point as decimal", amount, "1.5", de, nil},
+		{"de point without group", amount, "1234.5", de, nil},
+		{"de point in integer", integer, "3.14", de, nil},
+		{"de short group", integer, "12.34.567", de, nil},
+		{"de group", integer, "-1.234", de, int64(-1234)},
+		{"de group and decimal", amount, "1.234,5", de, 1234.5},
+		{"de group in fraction", amount, "1,234.5", de, nil},
+		{"fr spaces", integer, "1 234 567", fr, int64(1234567)},
+		{"fr short group", integer, "12 34", fr, nil},
+		{"fr decimal", amount, "1,5", fr, 1.5},
+		{"en comma as decimal", integer, "1,2", en, nil},
+		{"en group", integer, "1,234", en, int64(1234)},
+		{"en leading group", integer, ",234", en, nil},
+		{"hi groups", integer, "1,23,45,678", hi, int64(12345678)},
+		{"hi western groups", integer, "1,234,567", hi, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseValue(tt.f, tt.s, tt.p)
+			if tt.want == nil {
+				assert.ErrorContains(t, err, string(seederrors.ValueType), "got %v", got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatTimeStamp(t *testing.T) {
+	en := NewPicker([]language.Tag{language.English}, nil)
+	zh := NewPicker([]language.Tag{language.Chinese}, nil)
+	und := NewPicker(nil, nil)
+	ts := time.Date(2023, 4, 5, 6, 7, 8, 120000000, time.FixedZone("", 8*3600))
+
+	tests := []struct {
+		name    string
+		setting TimeStampSetting
+		v       time.Time
+		p       *Picker
+		want    string
+	}{
+		{"date", TimeStampSetting{Scale: 24 * time.Hour}, ts.Truncate(24 * time.Hour), en, "Apr 4, 2023"},
+		{"zh date", TimeStampSetting{Scale: 24 * time.Hour}, ts.Truncate(24 * time.Hour), zh, "2023年4月4日"},
+		{"minutes", TimeStampSetting{Scale: time.Minute}, ts.Truncate(time.Minute).UTC(), und, "2023-04-04 22:07"},
+		{"seconds", TimeStampSetting{Scale: time.Second}, ts.Truncate(time.Second).UTC(), en, "Apr 4, 2023 22:07:08"},
+		{"millis", TimeStampSetting{Scale: time.Millisecond}, ts.UTC(), en, "Apr 4, 2023 22:07:08.120"},
+		{"offset", TimeStampSetting{Scale: time.Second, WithTimeZoneOffset: true}, ts.Truncate(time.Second), en, "Apr 5, 2023 06:07:08 +08:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setting.Max = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+			f := &Field{Thing: Thing{Name: "at"}, FieldType: TimeStamp, FieldTypeSetting: tt.setting}
+			got, err := FormatValue(f, tt.v, tt.p)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			parsed, err := ParseValue(f, got, tt.p)
+			require.NoError(t, err)
+			assert.True(t, tt.v.Equal(parsed.(time.Time)), parsed)
+			require.NoError(t, f.CheckValue(parsed))
+		})
+	}
+
+	f := &Field{Thing: Thing{Name: "at"}, FieldType: TimeStamp, FieldTypeSetting: TimeStampSetting{Scale: time.Second}}
+	parsed, err := ParseValue(f, "2023-04-05T06:07:08+08:00", en)
+	require.NoError(t, err)
+	assert.Equal(t, ts.Truncate(time.Second).UTC(), parsed, "RFC 3339 in UTC")
+}
+
+func TestFormatEnumerationAndList(t *testing.T) {
+	en := NewPicker([]language.Tag{language.English}, nil)
+	zh := NewPicker([]language.Tag{language.Chinese}, nil)
+	color := EnumerationSetting{Values: []EnumerationValue{
+		{Thing: Thing{Name: "red", Label: I18n[string]{language.English: "Red", language.Chinese: "红"}}},
+		{Thing: Thing{Name: "blue", Label: I18n[string]{language.English: "Blue", language.Chinese: "蓝"}}},
+		{Thing: Thing{Name: "green"}},
+	}}
+	colors := &Field{Thing: Thing{Name: "colors"}, FieldType: List, FieldTypeSetting: ListSetting{
+		MaxLength: 3, ItemType: Enumeration, ItemTypeSetting: color,
+	}}
+	assert.Equal(t, "Red, Blue, green", must.V(FormatValue(colors, []CodeName{"red", "blue", "green"}, en)))
+	assert.Equal(t, "红、蓝", must.V(FormatValue(colors, []string{"red", "blue"}, zh)))
+	assert.Equal(t, []any{CodeName("red"), CodeName("blue")}, must.V(ParseValue(colors, "红、蓝", zh)))
+	assert.Equal(t, []any{CodeName("red"), CodeName("green")}, must.V(ParseValue(colors, "red, Green", en)))
+	_, err := ParseValue(colors, "Red, Pink", en)
+	assert.ErrorContains(t, err, "colors.1")
+
+	amounts := &Field{Thing: Thing{Name: "amounts"}, FieldType: List, FieldTypeSetting: ListSetting{
+		MaxLength: 3, ItemType: Integer, ItemTypeSetting: Int64Setting(),
+	}}
+	assert.Equal(t, "1,000, 2,000", must.V(FormatValue(amounts, []int64{1000, 2000}, en)))
+	assert.Equal(t, []any{int64(1000), int64(2000)}, must.V(ParseValue(amounts, "1,000, 2,000", en)))
+
+	name := &Field{Thing: Thing{Name: "name"}, FieldType: String, FieldTypeSetting: StringSetting{MaxCodePoints: 10}, IsI18n: true}
+	assert.Equal(t, "名字", must.V(FormatValue(name, map[language.Tag]string{language.English: "name", language.Chinese: "名字"}, zh)))
+	_, err = ParseValue(name, "name", en)
+	assert.ErrorAs(t, err, &seederrors.FieldNotSupportedError{})
+
+	ref := &Field{Thing: Thing{Name: "ref"}, FieldType: Reference, FieldTypeSetting: ReferenceSetting{}}
+	_, err = FormatValue(ref, "x", en)
+	assert.ErrorAs(t, err, &seederrors.FieldNotSupportedError{})
+}
@@ -8,6 +8,7 @@ require (
 	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/porfirion/trie v0.0.1
 	github.com/puzpuzpuz/xsync/v2 v2.4.0
+	github.com/shopspring/decimal v1.3.1
 	github.com/stretchr/testify v1.8.1
 	github.com/wk8/go-ordered-map/v2 v2.1.5
 	github.com/xiegeo/must v0.0.2-0.20221215091440-60fce374ce03
@@ -0,0 +1,430 @@
+// Package gogen generates Go source code from a domain, with one struct per object,
+// constants for every CodeName, and conversions to and from map[seed.CodeName]any.
+//
+// Generated code lets application code fail to compile when the domain drops a field,
+// instead of failing at runtime.
+package gogen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/language"
+
+	"github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+const (
+	importSeed    = "github.com/xiegeo/seed"
+	importTime    = "time"
+	importBig     = "math/big"
+	importDecimal = "github.com/shopspring/decimal"
+)
+
+// Options of Generate.
+type Options struct {
+	PackageName string // name of the generated package, required.
+}
+
+// Generate writes Go source code for all objects in d to w. The output is gofmt-ed.
+func Generate(w io.Writer, d seed.DomainGetter, opt Options) error {
+	if opt.PackageName == "" {
+		return seederrors.NewSystemError("package name is required")
+	}
+	g := &generator{
+		imports:  map[string]bool{importSeed: true},
+		declared: map[string]string{},
+	}
+	err := g.domain(d)
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by seedgen from domain %q. DO NOT EDIT.\n\n", d.GetName())
+	fmt.Fprintf(&out, "package %s\n\n", opt.PackageName)
+	g.writeImports(&out)
+	_, _ = out.Write(g.body.Bytes())
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return seederrors.NewSystemError("generated code is not valid: %v", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+type generator struct {
+	body     bytes.Buffer
+	imports  map[string]bool
+	declared map[string]string // package level Go names to what they are declared for
+	queue    []*goStruct       // structs waiting to be written
+}
+
+// declare reserves a package level Go name, and returns an error if it is already used,
+// such as by an object "event_time" and the combination field "time" of an object "event".
+func (g *generator) declare(name, what string) error {
+	if other, ok := g.declared[name]; ok {
+		return seederrors.NewSystemError("Go name %s of %s is already used by %s", name, what, other)
+	}
+	g.declared[name] = what
+	return nil
+}
+
+// goStruct is a struct generated for an object or a combination field.
+type goStruct struct {
+	name   string
+	thing  seed.ThingGetter
+	group  seed.FieldGroupGetter
+	nested bool // true for combinations
+	fields []goField
+	enums  []goEnum
+}
+
+type goField struct {
+	field     *seed.Field
+	name      string // Go name of the struct field
+	constName string // Go name of the field name constant
+	typ       goType
+}
+
+type goEnum struct {
+	constName string
+	value     seed.CodeName
+}
+
+// goType describes the Go type of a value and how it is converted.
+type goType struct {
+	expr    string  // the Go type expression
+	object  string  // name of the generated struct for combinations
+	item    *goType // item type for lists
+	pointer bool    // pointer added to hold null
+}
+
+func (g *generator) domain(d seed.DomainGetter) error {
+	objects := d.GetObjects().Values()
+	for _, ob := range objects {
+		if err := g.declare("Object"+GoName(ob.GetName()), fmt.Sprintf("the name of object %s", ob.GetName())); err != nil {
+			return err
+		}
+	}
+	if len(objects) > 0 {
+		fmt.Fprintf(&g.body, "// Names of objects in domain %q.\nconst (\n", d.GetName())
+		for _, ob := range objects {
+			fmt.Fprintf(&g.body, "\tObject%s seed.CodeName = %q\n", GoName(ob.GetName()), ob.GetName())
+		}
+		g.body.WriteString(")\n")
+	}
+	for _, ob := range objects {
+		g.queue = append(g.queue, &goStruct{name: GoName(ob.GetName()), thing: ob, group: ob})
+		for len(g.queue) > 0 {
+			s := g.queue[0]
+			g.queue = g.queue[1:]
+			err := g.goStruct(s)
+			if err != nil {
+				return seederrors.WithMessagef(err, "in object %s", ob.GetName())
+			}
+		}
+	}
+	return nil
+}
+
+func (g *generator) goStruct(s *goStruct) error {
+	var fields []*seed.Field
+	if getter := s.group.GetFields(); getter != nil {
+		fields = getter.Values()
+	}
+	for _, f := range fields {
+		name := GoName(f.Name)
+		if name == "ToMap" {
+			return seederrors.NewFieldNotSupportedError(f.FieldType.String(), f.Name, "name", "conflicts with method ToMap")
+		}
+		typ, err := g.goType(s, name, f)
+		if err != nil {
+			return err
+		}
+		s.fields = append(s.fields, goField{
+			field:     f,
+			name:      name,
+			constName: "Field" + s.name + name,
+			typ:       typ,
+		})
+	}
+	if err := g.declareStruct(s); err != nil {
+		return err
+	}
+	g.writeConsts(s)
+	g.writeStruct(s)
+	g.writeToMap(s)
+	g.writeFromMap(s)
+	return nil
+}
+
+func (g *generator) declareStruct(s *goStruct) error {
+	what := fmt.Sprintf("object %s", s.thing.GetName())
+	if s.nested {
+		what = fmt.Sprintf("combination field %s", s.thing.GetName())
+	}
+	if err := g.declare(s.name, what); err != nil {
+		return err
+	}
+	if err := g.declare(s.name+"FromMap", "the FromMap function of "+what); err != nil {
+		return err
+	}
+	for _, f := range s.fields {
+		if err := g.declare(f.constName, fmt.Sprintf("the name of field %s in %s", f.field.Name, what)); err != nil {
+			return err
+		}
+	}
+	for _, e := range s.enums {
+		if err := g.declare(e.constName, fmt.Sprintf("the enumeration value %s in %s", e.value, what)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// goType returns the type of f, and queues structs for combinations.
+func (g *generator) goType(s *goStruct, name string, f *seed.Field) (goType, error) {
+	var t goType
+	nilable := false
+	switch vt := f.FieldTypeSetting.(type) {
+	case seed.StringSetting:
+		t.expr, nilable = i18nType(f, "string"), f.IsI18n
+	case seed.BinarySetting:
+		t.expr, nilable = i18nType(f, "[]byte"), true
+	case seed.BooleanSetting:
+		t.expr = "bool"
+	case seed.TimeStampSetting:
+		g.imports[importTime] = true
+		t.expr = "time.Time"
+	case seed.IntegerSetting:
+		if seed.Int64Setting().Covers(vt) {
+			t.expr = "int64"
+		} else {
+			g.imports[importBig] = true
+			t.expr, nilable = "*big.Int", true
+		}
+	case seed.RealSetting:
+		switch {
+		case vt.Standard == seed.CustomReal:
+			return t, seederrors.NewFieldNotSupportedError(f.FieldType.String(), f.Name, vt.Standard.String(), "Standard")
+		case vt.Standard.IsDecimal():
+			g.imports[importDecimal] = true
+			t.expr = "decimal.Decimal"
+		default:
+			t.expr = "float64"
+		}
+	case seed.ReferenceSetting:
+		t.expr, nilable = "map[seed.CodeName]any", true
+	case seed.EnumerationSetting:
+		t.expr = "seed.CodeName"
+		for _, v := range vt.Values {
+			s.enums = append(s.enums, goEnum{constName: s.name + name + GoName(v.Name), value: v.Name})
+		}
+	case seed.ListSetting:
+		if vt.ItemType == seed.List {
+			return t, seederrors.NewFieldNotSupportedError(f.FieldType.String(), f.Name, vt.ItemType.String(), "ItemType")
+		}
+		item, err := g.goType(s, name, vt.ItemField(f))
+		if err != nil {
+			return t, err
+		}
+		t.expr, t.item = "[]"+item.expr, &item
+		return t, nil // null is an empty list
+	case seed.CombinationSetting:
+		t.object = s.name + name
+		t.expr = t.object
+		g.queue = append(g.queue, &goStruct{name: t.object, thing: f, group: &vt, nested: true})
+	default:
+		return t, seederrors.NewFieldNotSupportedError(f.FieldType.String(), f.Name)
+	}
+	if f.IsI18n && !strings.HasPrefix(t.expr, "seed.I18n") {
+		return t, seederrors.NewFieldNotSupportedError(f.FieldType.String(), f.Name, "true", "IsI18n")
+	}
+	if f.Nullable && !nilable {
+		t.expr, t.pointer = "*"+t.expr, true
+	}
+	return t, nil
+}
+
+func i18nType(f *seed.Field, expr string) string {
+	if f.IsI18n {
+		return "seed.I18n[" + expr + "]"
+	}
+	return expr
+}
+
+func (g *generator) writeImports(out *bytes.Buffer) {
+	var std, other []string
+	for p := range g.imports {
+		if strings.Contains(strings.Split(p, "/")[0], ".") {
+			other = append(other, p)
+		} else {
+			std = append(std, p)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	out.WriteString("import (\n")
+	for _, p := range std {
+		fmt.Fprintf(out, "\t%q\n", p)
+	}
+	if len(std) > 0 {
+		out.WriteString("\n")
+	}
+	for _, p := range other {
+		fmt.Fprintf(out, "\t%q\n", p)
+	}
+	out.WriteString(")\n\n")
+}
+
+func (g *generator) writeConsts(s *goStruct) {
+	if len(s.fields) > 0 {
+		fmt.Fprintf(&g.body, "\n// Names of fields in %s.\nconst (\n", s.name)
+		for _, f := range s.fields {
+			fmt.Fprintf(&g.body, "\t%s seed.CodeName = %q\n", f.constName, f.field.Name)
+		}
+		g.body.WriteString(")\n")
+	}
+	if len(s.enums) > 0 {
+		fmt.Fprintf(&g.body, "\n// Enumeration values of fields in %s.\nconst (\n", s.name)
+		for _, e := range s.enums {
+			fmt.Fprintf(&g.body, "\t%s seed.CodeName = %q\n", e.constName, e.value)
+		}
+		g.body.WriteString(")\n")
+	}
+}
+
+func (g *generator) writeStruct(s *goStruct) {
+	kind := "object"
+	if s.nested {
+		kind = "combination field"
+	}
+	fmt.Fprintf(&g.body, "\n// %s holds the values of %s %q.\n", s.name, kind, s.thing.GetName())
+	writeDescription(&g.body, s.thing)
+	fmt.Fprintf(&g.body, "type %s struct {\n", s.name)
+	for _, f := range s.fields {
+		label := englishText(f.field.GetLabel())
+		if label != "" && !strings.EqualFold(strings.ReplaceAll(label, " ", ""), f.name) {
+			fmt.Fprintf(&g.body, "\t%s %s // %s\n", f.name, f.typ.expr, strings.ReplaceAll(label, "\n", " "))
+		} else {
+			fmt.Fprintf(&g.body, "\t%s %s\n", f.name, f.typ.expr)
+		}
+	}
+	g.body.WriteString("}\n")
+}
+
+func (g *generator) writeToMap(s *goStruct) {
+	fmt.Fprintf(&g.body, "\n// ToMap returns the values of v keyed by field names, as accepted by persistence layers.\n")
+	fmt.Fprintf(&g.body, "func (v *%s) ToMap() map[seed.CodeName]any {\n", s.name)
+	fmt.Fprintf(&g.body, "\tm := make(map[seed.CodeName]any, %d)\n", len(s.fields))
+	for _, f := range s.fields {
+		switch {
+		case f.typ.item != nil && f.typ.item.object != "":
+			fmt.Fprintf(&g.body, "\t{\n\t\titems := make([]any, len(v.%s))\n", f.name)
+			fmt.Fprintf(&g.body, "\t\tfor i := range v.%s {\n\t\t\titems[i] = v.%[1]s[i].ToMap()\n\t\t}\n", f.name)
+			fmt.Fprintf(&g.body, "\t\tm[%s] = items\n\t}\n", f.constName)
+		case f.typ.pointer:
+			value := "*v." + f.name
+			if f.typ.object != "" {
+				value = "v." + f.name + ".ToMap()"
+			}
+			fmt.Fprintf(&g.body, "\tm[%s] = nil\n\tif v.%s != nil {\n\t\tm[%[1]s] = %[3]s\n\t}\n", f.constName, f.name, value)
+		case f.typ.object != "":
+			fmt.Fprintf(&g.body, "\tm[%s] = v.%s.ToMap()\n", f.constName, f.name)
+		default:
+			fmt.Fprintf(&g.body, "\tm[%s] = v.%s\n", f.constName, f.name)
+		}
+	}
+	g.body.WriteString("\treturn m\n}\n")
+}
+
+func (g *generator) writeFromMap(s *goStruct) {
+	fmt.Fprintf(&g.body, "\n// %sFromMap reads values in the form of ToMap, such as values read from a persistence layer.\n", s.name)
+	fmt.Fprintf(&g.body, "func %sFromMap(m map[seed.CodeName]any) (%[1]s, error) {\n\tvar v %[1]s\n", s.name)
+	for _, f := range s.fields {
+		if f.typ.object == "" { // combinations declare their own err
+			g.body.WriteString("\tvar err error\n")
+			break
+		}
+	}
+	for _, f := range s.fields {
+		switch {
+		case f.typ.item != nil && f.typ.item.object != "":
+			fmt.Fprintf(&g.body, "\tv.%s, err = seed.ConvertList(m[%s], func(item any) (%s, error) {\n", f.name, f.constName, f.typ.item.object)
+			fmt.Fprintf(&g.body, "\t\tsub, err := seed.ConvertValue[map[seed.CodeName]any](item)\n")
+			fmt.Fprintf(&g.body, "\t\tif err != nil {\n\t\t\treturn %s{}, err\n\t\t}\n", f.typ.item.object)
+			fmt.Fprintf(&g.body, "\t\treturn %sFromMap(sub)\n\t})\n", f.typ.item.object)
+		case f.typ.item != nil:
+			fmt.Fprintf(&g.body, "\tv.%s, err = seed.ConvertList(m[%s], seed.ConvertValue[%s])\n", f.name, f.constName, f.typ.item.expr)
+		case f.typ.object != "":
+			fmt.Fprintf(&g.body, "\tif sub, err := seed.MapValue[map[seed.CodeName]any](m, %s); err != nil {\n\t\treturn v, err\n", f.constName)
+			if f.typ.pointer {
+				fmt.Fprintf(&g.body, "\t} else if sub != nil {\n\t\tvalue, err := %sFromMap(sub)\n", f.typ.object)
+				fmt.Fprintf(&g.body, "\t\tif err != nil {\n\t\t\treturn v, err\n\t\t}\n\t\tv.%s = &value\n\t}\n", f.name)
+			} else {
+				fmt.Fprintf(&g.body, "\t} else if v.%s, err = %sFromMap(sub); err != nil {\n\t\treturn v, err\n\t}\n", f.name, f.typ.object)
+			}
+			continue
+		default:
+			fmt.Fprintf(&g.body, "\tv.%s, err = seed.MapValue[%s](m, %s)\n", f.name, f.typ.expr, f.constName)
+		}
+		g.body.WriteString("\tif err != nil {\n\t\treturn v, err\n\t}\n")
+	}
+	g.body.WriteString("\treturn v, nil\n}\n")
+}
+
+func writeDescription(out *bytes.Buffer, thing seed.ThingGetter) {
+	description := englishText(thing.GetDescription())
+	if description == "" {
+		return
+	}
+	out.WriteString("//\n")
+	for _, line := range strings.Split(description, "\n") {
+		fmt.Fprintf(out, "// %s\n", line)
+	}
+}
+
+// englishText returns the English text for comments.
+func englishText(getter seed.I18nGetter[string]) string {
+	if getter == nil {
+		return ""
+	}
+	var text string
+	getter.RangeAll(func(tag language.Tag, v string) {
+		if tag == language.English {
+			text = v
+		}
+	})
+	return strings.TrimSpace(text)
+}
+
+// initialisms are written in upper case by GoName, following Go naming conventions.
+var initialisms = map[string]bool{
+	"api": true, "html": true, "http": true, "id": true, "ip": true, "json": true,
+	"sql": true, "uri": true, "url": true, "uuid": true, "xml": true,
+}
+
+// GoName returns the exported Go name of a CodeName, such as "number_of_guests" to
+// "NumberOfGuests" and "user_id" to "UserID".
+func GoName(name seed.CodeName) string {
+	var sb strings.Builder
+	for _, part := range strings.Split(string(name), "_") {
+		if initialisms[strings.ToLower(part)] {
+			sb.WriteString(strings.ToUpper(part))
+			continue
+		}
+		for i, r := range part {
+			if i == 0 {
+				r = unicode.ToUpper(r)
+			}
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,118 @@
+package gogen_test
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
+
+	"github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/demo/testdomain"
+	"github.com/xiegeo/seed/gogen"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+func combination(name seed.CodeName, fields ...*seed.Field) *seed.Field {
+	return &seed.Field{
+		Thing:            seed.Thing{Name: name},
+		FieldType:        seed.Combination,
+		FieldTypeSetting: seed.CombinationSetting{Fields: must.V(seed.NewFields(fields...))},
+	}
+}
+
+func testDomain() *seed.Domain {
+	note := testdomain.TextAreaField()
+	note.Nullable = true
+	title := testdomain.TextLineField()
+	title.IsI18n = true
+	address := combination("address", testdomain.TextLineField(), testdomain.Bool())
+	address.Nullable = true
+	line := combination("line", testdomain.Decimal64(), testdomain.JSInteger())
+	lines := testdomain.ListOf(line, seed.ListSetting{MaxLength: 10})
+	return must.V(seed.NewDomain(seed.Thing{Name: "shop"}, &seed.Object{
+		Thing: seed.Thing{Name: "order"},
+		FieldGroup: seed.FieldGroup{Fields: must.V(seed.NewFields(
+			testdomain.DateTimeSec(),
+			note,
+			title,
+			testdomain.BigMax(testdomain.Integer64(), 2),
+			testdomain.Enumeration(),
+			testdomain.ListOf(testdomain.TextLineField(), seed.ListSetting{MaxLength: 3}),
+			address,
+			lines,
+		))},
+	}))
+}
+
+func TestGenerate(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, gogen.Generate(&buf, testDomain(), gogen.Options{PackageName: "shop"}))
+	out := buf.String()
+	for _, want := range []string{
+		"// Code generated by seedgen from domain \"shop\". DO NOT EDIT.\n",
+		"\t\"math/big\"\n\t\"time\"\n\n\t\"github.com/shopspring/decimal\"\n\t\"github.com/xiegeo/seed\"\n",
+		"ObjectOrder seed.CodeName = \"order\"",
+		"FieldOrderDatetimeSec9999  seed.CodeName = \"datetime_sec_9999\"",
+		"OrderStatusPublished seed.CodeName = \"published\"",
+		"\tTextArea3000     *string ",
+		"\tText10           seed.I18n[string] ",
+		"\tMax64e2Integer64 *big.Int ",
+		"\tStatus           seed.CodeName\n",
+		"\tAddress          *OrderAddress\n",
+		"\tListLine         []OrderListLine ",
+		"\tDecimal64 decimal.Decimal ",
+		"v.ListText10, err = seed.ConvertList(m[FieldOrderListText10], seed.ConvertValue[string])",
+		"func OrderAddressFromMap(m map[seed.CodeName]any) (OrderAddress, error) {",
+	} {
+		assert.Contains(t, out, want)
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "shop.go", out, 0)
+	require.NoError(t, err)
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = conf.Check("shop", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+}
+
+func TestGenerateNotSupported(t *testing.T) {
+	ob := testdomain.ObjLevel0()
+	ob.Fields = must.V(seed.NewFields(&seed.Field{
+		Thing:            seed.Thing{Name: "custom"},
+		FieldType:        seed.Real,
+		FieldTypeSetting: seed.RealSetting{Standard: seed.CustomReal},
+	}))
+	d := must.V(seed.NewDomain(seed.Thing{Name: "bad"}, ob))
+	err := gogen.Generate(&bytes.Buffer{}, d, gogen.Options{PackageName: "bad"})
+	assert.ErrorAs(t, err, &seederrors.FieldNotSupportedError{})
+	err = gogen.Generate(&bytes.Buffer{}, testDomain(), gogen.Options{})
+	assert.Error(t, err)
+}
+
+func TestGenerateNameConflict(t *testing.T) {
+	event := testdomain.ObjLevel0()
+	event.Name = "event"
+	event.Fields = must.V(seed.NewFields(combination("time", testdomain.DateTimeSec())))
+	eventTime := testdomain.ObjLevel0()
+	eventTime.Name = "event_time"
+	d := must.V(seed.NewDomain(seed.Thing{Name: "conflict"}, event, eventTime))
+	err := gogen.Generate(&bytes.Buffer{}, d, gogen.Options{PackageName: "conflict"})
+	assert.ErrorContains(t, err, "EventTime")
+}
+
+func TestGoName(t *testing.T) {
+	for name, want := range map[seed.CodeName]string{
+		"number_of_guests": "NumberOfGuests",
+		"user_id":          "UserID",
+		"camelCase":        "CamelCase",
+		"v2":               "V2",
+	} {
+		assert.Equal(t, want, gogen.GoName(name))
+	}
+}
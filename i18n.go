@@ -37,13 +37,18 @@ func (n I18n[T]) RangeAll(f func(language.Tag, T)) {
 	}
 }
 
+// GetValue returns the value picked by p, or fallback if no values are picked.
+// Strings are pseudo-localized if p requests PseudoLanguage.
 func (n I18n[T]) GetValue(p *Picker, fallback T) T {
 	lang, _ := Pick[T](p, n)
 	v, ok := n[lang]
-	if ok {
-		return v
+	if !ok {
+		v = fallback
+	}
+	if p.isPseudo() {
+		return pseudoValue(v)
 	}
-	return fallback
+	return v
 }
 
 // Picker is an inverse language.Matcher, where user request language is static
@@ -66,6 +71,17 @@ func NewPicker(preferred []language.Tag, fallback *Picker) *Picker {
 	}
 }
 
+// Language returns the most preferred language of p, or of its fallbacks if p has no preferences.
+// It is und if no pickers in the chain have preferences.
+func (p *Picker) Language() language.Tag {
+	for ; p != nil; p = p.fallback {
+		if len(p.preferred) > 0 {
+			return p.preferred[0]
+		}
+	}
+	return language.Und
+}
+
 var (
 	_systemLogPicker           *Picker
 	_systemLogPickerOnce       sync.Once
@@ -139,6 +155,9 @@ func (p *Picker) getOrder(alternative language.Tag) pickOrder {
 	}
 	var bestOrder pickOrder
 	for i, speeker := range p.preferred {
+		if speeker == PseudoLanguage {
+			speeker = language.English // pseudo-localizes English text
+		}
 		c := language.Comprehends(speeker, alternative)
 		newOrder := pickOrder{confidence: c, order: i}
 		if newOrder.betterThen(bestOrder) {
@@ -0,0 +1,277 @@
+package seed
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// TranslationCatalog holds the labels and descriptions of a domain for translation from the
+// Source language to Language. It can be exchanged with translators as JSON, or as gettext PO
+// files by WritePO and ReadPO.
+type TranslationCatalog struct {
+	Language language.Tag
+	Source   language.Tag
+	Entries  []TranslationEntry
+}
+
+// TranslationEntry is a label or description to translate.
+type TranslationEntry struct {
+	Path   string // path of the thing, see TranslationCoverage
+	Text   string // Label or Description
+	Source string // text in the source language, English, or the code name of the thing
+	Value  string `json:",omitempty"` // translation, empty if not translated
+}
+
+// ExportTranslations returns the catalog of every label of d, and every description that is given,
+// with translations to target that already exist.
+func ExportTranslations(d DomainGetter, source, target language.Tag) TranslationCatalog {
+	c := TranslationCatalog{Language: target, Source: source}
+	sourcePicker := NewPicker([]language.Tag{source}, _lastPicker)
+	rangeThings(d, func(path []string, t *Thing) {
+		for _, text := range []string{"Label", "Description"} {
+			values := thingText(t, text)
+			if len(values) == 0 && text == "Description" {
+				continue
+			}
+			c.Entries = append(c.Entries, TranslationEntry{
+				Path:   strings.Join(path, "/"),
+				Text:   text,
+				Source: values.GetValue(sourcePicker, string(t.Name)),
+				Value:  values[target],
+			})
+		}
+	})
+	return c
+}
+
+func thingText(t *Thing, text string) I18n[string] {
+	if text == "Label" {
+		return t.Label
+	}
+	return t.Description
+}
+
+// TranslationImport reports the result of ImportTranslations.
+type TranslationImport struct {
+	Imported int                // number of translations set
+	Stale    []TranslationEntry // entries of things that no longer exist
+}
+
+// ImportTranslations sets the translations of c into the labels and descriptions of d.
+// Untranslated entries are skipped, and entries for paths that no longer exist in d are reported
+// as stale.
+func ImportTranslations(d *Domain, c TranslationCatalog) (TranslationImport, error) {
+	if c.Language == language.Und {
+		return TranslationImport{}, seederrors.NewValueRequiredError("Language")
+	}
+	things := make(map[string]*Thing)
+	rangeThings(d, func(path []string, t *Thing) {
+		things[strings.Join(path, "/")] = t
+	})
+	var r TranslationImport
+	for _, e := range c.Entries {
+		t, ok := things[e.Path]
+		if !ok || e.Text != "Label" && e.Text != "Description" {
+			r.Stale = append(r.Stale, e)
+			continue
+		}
+		if e.Value == "" {
+			continue
+		}
+		target := &t.Label
+		if e.Text == "Description" {
+			target = &t.Description
+		}
+		if *target == nil {
+			*target = make(I18n[string])
+		}
+		(*target)[c.Language] = e.Value
+		r.Imported++
+	}
+	return r, nil
+}
+
+// WritePO writes c as a gettext PO file. Each entry has its path and text as msgctxt, such as
+// "shop/order/Label", the source text as msgid, and the translation as msgstr.
+func (c TranslationCatalog) WritePO(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "msgid \"\"\nmsgstr \"\"\n%s\n%s\n%s\n",
+		poQuote("Content-Type: text/plain; charset=UTF-8\n"),
+		poQuote("Language: "+c.Language.String()+"\n"),
+		poQuote("X-Source-Language: "+c.Source.String()+"\n"))
+	for _, e := range c.Entries {
+		fmt.Fprintf(bw, "\nmsgctxt %s\nmsgid %s\nmsgstr %s\n",
+			poQuote(e.Path+"/"+e.Text), poQuote(e.Source), poQuote(e.Value))
+	}
+	return bw.Flush()
+}
+
+// poQuote quotes s as a PO string, which escapes like a Go string.
+func poQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// ReadPO reads a catalog from a gettext PO file written by WritePO and edited by translators.
+//
+// Only a subset of PO is supported: msgctxt, msgid and msgstr with strings continued on following
+// lines, and comments. Plural forms are not supported. Translations marked as fuzzy are read as
+// untranslated. Language and X-Source-Language are read from the header.
+func ReadPO(r io.Reader) (TranslationCatalog, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return TranslationCatalog{}, err
+	}
+	p := poParser{input: string(data)}
+	return p.parse()
+}
+
+type poParser struct {
+	input string
+	c     TranslationCatalog
+}
+
+type poMessage struct {
+	fields map[string]*string // keywords to their values
+	fuzzy  bool
+	offset int // of the first keyword
+}
+
+func (p *poParser) parse() (TranslationCatalog, error) {
+	msg := poMessage{fields: map[string]*string{}}
+	var last *string // value continued by following strings
+	next := func() error {
+		if msg.fields["msgstr"] == nil {
+			return nil // msg is not complete yet
+		}
+		err := p.add(msg)
+		msg = poMessage{fields: map[string]*string{}}
+		return err
+	}
+	offset := 0
+	for _, line := range strings.SplitAfter(p.input, "\n") {
+		lineOffset := offset
+		offset += len(line)
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			if err := next(); err != nil {
+				return TranslationCatalog{}, err
+			}
+			if strings.HasPrefix(line, "#,") && strings.Contains(line, "fuzzy") {
+				msg.fuzzy = true
+			}
+			last = nil
+			continue
+		}
+		keyword, quoted := "", line
+		if !strings.HasPrefix(line, `"`) {
+			keyword, quoted, _ = strings.Cut(line, " ")
+		}
+		s, err := strconv.Unquote(strings.TrimSpace(quoted))
+		if err != nil {
+			return TranslationCatalog{}, seederrors.NewSyntaxError(p.input, lineOffset, "string is not valid: %v", err)
+		}
+		switch keyword {
+		case "":
+			if last == nil {
+				return TranslationCatalog{}, seederrors.NewSyntaxError(p.input, lineOffset, "string is not after a keyword")
+			}
+			*last += s
+			continue
+		case "msgctxt", "msgid":
+			if err := next(); err != nil {
+				return TranslationCatalog{}, err
+			}
+		case "msgstr":
+		default:
+			return TranslationCatalog{}, seederrors.NewSyntaxError(p.input, lineOffset, "keyword %q is not supported", keyword)
+		}
+		if msg.fields[keyword] != nil {
+			return TranslationCatalog{}, seederrors.NewSyntaxError(p.input, lineOffset, "keyword %q is repeated", keyword)
+		}
+		if len(msg.fields) == 0 {
+			msg.offset = lineOffset
+		}
+		last = &s
+		msg.fields[keyword] = last
+	}
+	if len(msg.fields) > 0 {
+		if err := p.add(msg); err != nil {
+			return TranslationCatalog{}, err
+		}
+	}
+	return p.c, nil
+}
+
+func (p *poParser) add(msg poMessage) error {
+	id, str := msg.fields["msgid"], msg.fields["msgstr"]
+	if id == nil || str == nil {
+		return seederrors.NewSyntaxError(p.input, msg.offset, "message must have msgid and msgstr")
+	}
+	ctxt := msg.fields["msgctxt"]
+	if ctxt == nil {
+		if *id == "" {
+			return p.header(*str, msg.offset)
+		}
+		return seederrors.NewSyntaxError(p.input, msg.offset, "message must have msgctxt of the path and text")
+	}
+	i := strings.LastIndex(*ctxt, "/")
+	if i < 0 {
+		return seederrors.NewSyntaxError(p.input, msg.offset, "msgctxt %q is not a path and text", *ctxt)
+	}
+	e := TranslationEntry{Path: (*ctxt)[:i], Text: (*ctxt)[i+1:], Source: *id}
+	if !msg.fuzzy {
+		e.Value = *str
+	}
+	p.c.Entries = append(p.c.Entries, e)
+	return nil
+}
+
+func (p *poParser) header(s string, offset int) error {
+	for _, line := range strings.Split(s, "\n") {
+		key, value, _ := strings.Cut(line, ":")
+		var target *language.Tag
+		switch strings.TrimSpace(key) {
+		case "Language":
+			target = &p.c.Language
+		case "X-Source-Language":
+			target = &p.c.Source
+		default:
+			continue
+		}
+		tag, err := language.Parse(strings.TrimSpace(value))
+		if err != nil {
+			return seederrors.NewSyntaxError(p.input, offset, "%s is not valid: %v", key, err)
+		}
+		*target = tag
+	}
+	return nil
+}
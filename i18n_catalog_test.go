@@ -0,0 +1,100 @@
+package seed_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+
+	. "github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+func TestExportTranslations(t *testing.T) {
+	d := translationTestDomain()
+	c := ExportTranslations(d, language.English, language.Chinese)
+	assert.Equal(t, language.Chinese, c.Language)
+	assert.Equal(t, []TranslationEntry{
+		{Path: "shop", Text: "Label", Source: "Shop", Value: "商店"},
+		{Path: "shop/order", Text: "Label", Source: "Order", Value: "订单"},
+		{Path: "shop/order", Text: "Description", Source: "A customer order"},
+		{Path: "shop/order/number", Text: "Label", Source: "Number", Value: "编号"},
+		{Path: "shop/order/weight", Text: "Label", Source: "Weight", Value: "重量"},
+		{Path: "shop/order/weight/Unit", Text: "Label", Source: "Kilogram"},
+		{Path: "shop/order/status", Text: "Label", Source: "status", Value: "状态"},
+		{Path: "shop/order/status/Values/open", Text: "Label", Source: "Open", Value: "打开"},
+		{Path: "shop/order/status/Values/closed", Text: "Label", Source: "closed"},
+		{Path: "shop/order/Identities/by_number", Text: "Label", Source: "By number", Value: "按编号"},
+	}, c.Entries)
+
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), `{"Language":"zh","Source":"en","Entries":[{"Path":"shop","Text":"Label","Source":"Shop","Value":"商店"}`), string(data))
+	var decoded TranslationCatalog
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, c, decoded)
+
+	var po bytes.Buffer
+	require.NoError(t, c.WritePO(&po))
+	assert.Contains(t, po.String(), "\nmsgctxt \"shop/order/status/Label\"\nmsgid \"status\"\nmsgstr \"状态\"\n")
+	read, err := ReadPO(&po)
+	require.NoError(t, err)
+	assert.Equal(t, c, read)
+}
+
+func TestImportTranslations(t *testing.T) {
+	d := translationTestDomain()
+	c, err := ReadPO(strings.NewReader(`# translated by hand
+msgid ""
+msgstr ""
+"Language: zh\n"
+"X-Source-Language: en\n"
+
+msgctxt "shop/order/Description"
+msgid "A customer order"
+msgstr "客户的"
+"订单"
+
+#, fuzzy
+msgctxt "shop/order/weight/Unit/Label"
+msgid "Kilogram"
+msgstr "公斤？"
+
+msgctxt "shop/order/status/Values/closed/Label"
+msgid "closed"
+msgstr "已关闭"
+
+msgctxt "shop/order/status/Values/open/Label"
+msgid "Open"
+msgstr ""
+
+msgctxt "shop/order/removed/Label"
+msgid "Removed"
+msgstr "已删除"
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "", c.Entries[1].Value, "fuzzy")
+
+	r, err := ImportTranslations(d, c)
+	require.NoError(t, err)
+	assert.Equal(t, 2, r.Imported)
+	assert.Equal(t, []TranslationEntry{{Path: "shop/order/removed", Text: "Label", Source: "Removed", Value: "已删除"}}, r.Stale)
+
+	ob, _ := d.Objects.Get("order")
+	assert.Equal(t, "客户的订单", ob.Description[language.Chinese])
+	status, _ := ob.Fields.Get("status")
+	assert.Equal(t, I18n[string]{language.Chinese: "已关闭"}, status.FieldTypeSetting.(EnumerationSetting).Values[1].Label)
+	assert.Equal(t, "打开", status.FieldTypeSetting.(EnumerationSetting).Values[0].Label[language.Chinese], "untranslated entries are skipped")
+	weight, _ := ob.Fields.Get("weight")
+	assert.Len(t, weight.Unit().Label, 1)
+
+	_, err = ImportTranslations(d, TranslationCatalog{})
+	assert.Error(t, err)
+	_, err = ReadPO(strings.NewReader("msgid \"a\"\nmsgstr \"b\"\nmsgfoo \"c\"\n"))
+	assert.ErrorAs(t, err, &seederrors.SyntaxError{})
+	assert.ErrorContains(t, err, "line 3 column 1")
+}
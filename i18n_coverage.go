@@ -0,0 +1,175 @@
+package seed
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/xiegeo/must"
+	"golang.org/x/text/language"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// rangeThings calls f with every thing in d that has labels, and its path: the domain, objects,
+// fields, identities, ranges, units and enumeration values. Paths are made of code names and the
+// capitalized keywords Identities, Ranges, Item, Unit and Values, such as:
+//
+//	shop/order/quantity/Unit
+//	shop/order/Identities/by_number/Ranges/period
+//	shop/order/tags/Item/Values/open
+//
+// Identities and ranges without names are given by their index, such as shop/order/Identities/0.
+//
+// Things of *Domain are given by pointers into d; things of other DomainGetters may be copies.
+func rangeThings(d DomainGetter, f func(path []string, t *Thing)) {
+	domainPath := []string{string(d.GetName())}
+	if dd, ok := d.(*Domain); ok {
+		f(domainPath, &dd.Thing)
+	} else {
+		t := NewThing(d)
+		f(domainPath, &t)
+	}
+	must.NoError(d.GetObjects().RangeLogical(func(cn CodeName, ob ObjectGetter) error {
+		path := withPath(domainPath, string(cn))
+		if o, ok := ob.(*Object); ok {
+			f(path, &o.Thing)
+		} else {
+			t := NewThing(ob)
+			f(path, &t)
+		}
+		rangeFieldGroupThings(ob, path, f)
+		return nil
+	}))
+}
+
+func rangeFieldGroupThings(g FieldGroupGetter, path []string, f func(path []string, t *Thing)) {
+	if fields := g.GetFields(); fields != nil {
+		must.NoError(fields.RangeLogical(func(cn CodeName, field *Field) error {
+			fieldPath := withPath(path, string(cn))
+			f(fieldPath, &field.Thing)
+			rangeSettingThings(field.FieldTypeSetting, fieldPath, f)
+			return nil
+		}))
+	}
+	ids := g.GetIdentities()
+	for i := range ids {
+		idPath := withPath(path, "Identities", nameOrIndex(ids[i].Name, i))
+		f(idPath, &ids[i].Thing)
+		for j := range ids[i].Ranges {
+			f(withPath(idPath, "Ranges", nameOrIndex(ids[i].Ranges[j].Name, j)), &ids[i].Ranges[j].Thing)
+		}
+	}
+	ranges := g.GetRanges()
+	for i := range ranges {
+		f(withPath(path, "Ranges", nameOrIndex(ranges[i].Name, i)), &ranges[i].Thing)
+	}
+}
+
+// nameOrIndex returns name as a path segment, or i if name is empty. Indexes never collide with
+// names, as code names can not start with a digit.
+func nameOrIndex(name CodeName, i int) string {
+	if name == "" {
+		return strconv.Itoa(i)
+	}
+	return string(name)
+}
+
+func rangeSettingThings(s FieldTypeSetting, path []string, f func(path []string, t *Thing)) {
+	switch vt := s.(type) {
+	case IntegerSetting:
+		if vt.Unit != nil {
+			f(withPath(path, "Unit"), &vt.Unit.Thing)
+		}
+	case RealSetting:
+		if vt.Unit != nil {
+			f(withPath(path, "Unit"), &vt.Unit.Thing)
+		}
+	case ListSetting:
+		rangeSettingThings(vt.ItemTypeSetting, withPath(path, "Item"), f)
+	case CombinationSetting:
+		rangeFieldGroupThings(&vt, path, f)
+	case EnumerationSetting:
+		for i := range vt.Values {
+			f(withPath(path, "Values", string(vt.Values[i].Name)), &vt.Values[i].Thing)
+		}
+	}
+}
+
+// TranslationGap is a label or description that misses translations of required languages.
+type TranslationGap struct {
+	Path     string         // path of the thing, such as shop/order/quantity
+	Text     string         // Label or Description
+	Missing  []language.Tag // required languages without translations
+	Fallback bool           // true if English is shown by the picker of last resort, false if nothing is shown
+}
+
+// TranslationReport lists the translations missing from a domain.
+type TranslationReport struct {
+	Languages []language.Tag // required languages
+	Things    int            // number of things checked
+	Gaps      []TranslationGap
+}
+
+// TranslationCoverage reports every thing of d with a label that is missing a translation in a
+// language of required, or a description that is given but is missing a translation. Things without
+// labels are reported, as their code names are shown instead. Descriptions are optional.
+//
+// A translation is not missing if it is in a language comprehended with high confidence by readers of
+// the required language, such as en-US for en, see language.Comprehends.
+func TranslationCoverage(d DomainGetter, required []language.Tag) TranslationReport {
+	r := TranslationReport{Languages: required}
+	rangeThings(d, func(path []string, t *Thing) {
+		r.Things++
+		r.check(path, "Label", t.Label, true)
+		r.check(path, "Description", t.Description, false)
+	})
+	return r
+}
+
+func (r *TranslationReport) check(path []string, text string, values I18n[string], required bool) {
+	if len(values) == 0 && !required {
+		return
+	}
+	var missing []language.Tag
+	for _, tag := range r.Languages {
+		if !hasTranslation(tag, values) {
+			missing = append(missing, tag)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	_, fallback := Pick[string](_lastPicker, values)
+	r.Gaps = append(r.Gaps, TranslationGap{
+		Path:     strings.Join(path, "/"),
+		Text:     text,
+		Missing:  missing,
+		Fallback: fallback,
+	})
+}
+
+func hasTranslation(tag language.Tag, values I18n[string]) bool {
+	for alternative, v := range values {
+		if v != "" && language.Comprehends(tag, alternative) >= language.High {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns the gaps of r as seederrors.DefinitionErrors, or nil if there are no gaps.
+func (r TranslationReport) Err() error {
+	if len(r.Gaps) == 0 {
+		return nil
+	}
+	errs := make(seederrors.DefinitionErrors, len(r.Gaps))
+	for i, gap := range r.Gaps {
+		tags := make([]string, len(gap.Missing))
+		for j, tag := range gap.Missing {
+			tags[j] = tag.String()
+		}
+		errs[i] = seederrors.NewDefinitionError(seederrors.DefinitionTranslation, strings.Join(tags, ","),
+			withPath(strings.Split(gap.Path, "/"), gap.Text)...)
+	}
+	return errs
+}
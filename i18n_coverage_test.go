@@ -0,0 +1,116 @@
+package seed_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
+	"golang.org/x/text/language"
+
+	. "github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+func labels(en, zh string) I18n[string] {
+	out := I18n[string]{}
+	if en != "" {
+		out[language.English] = en
+	}
+	if zh != "" {
+		out[language.Chinese] = zh
+	}
+	return out
+}
+
+func translationTestDomain() *Domain {
+	return must.V(NewDomain(Thing{Name: "shop", Label: labels("Shop", "商店")}, &Object{
+		Thing: Thing{Name: "order", Label: labels("Order", "订单"), Description: labels("A customer order", "")},
+		FieldGroup: FieldGroup{
+			Fields: must.V(NewFields(
+				&Field{
+					Thing:     Thing{Name: "number", Label: I18n[string]{language.AmericanEnglish: "Number", language.Chinese: "编号"}},
+					FieldType: Integer, FieldTypeSetting: Int64Setting(),
+				},
+				&Field{
+					Thing:     Thing{Name: "weight", Label: labels("Weight", "重量")},
+					FieldType: Integer,
+					FieldTypeSetting: IntegerSetting{Min: big.NewInt(0), Max: big.NewInt(100), Unit: &Unit{
+						Thing: Thing{Name: "kilogram", Label: labels("Kilogram", "")}, Symble: "kg",
+					}},
+				},
+				&Field{
+					Thing:     Thing{Name: "status", Label: labels("", "状态")},
+					FieldType: Enumeration,
+					FieldTypeSetting: EnumerationSetting{Values: []EnumerationValue{
+						{Thing: Thing{Name: "open", Label: labels("Open", "打开")}},
+						{Thing: Thing{Name: "closed"}},
+					}},
+				},
+			)),
+			Identities: []Identity{{Thing: Thing{Name: "by_number", Label: labels("By number", "按编号")}, Fields: []CodeName{"number"}}},
+		},
+	}))
+}
+
+func TestTranslationCoverage(t *testing.T) {
+	d := translationTestDomain()
+	require.NoError(t, d.Validate())
+	r := TranslationCoverage(d, []language.Tag{language.English, language.Chinese})
+	assert.Equal(t, 9, r.Things)
+	assert.Equal(t, []TranslationGap{
+		{Path: "shop/order", Text: "Description", Missing: []language.Tag{language.Chinese}, Fallback: true},
+		{Path: "shop/order/weight/Unit", Text: "Label", Missing: []language.Tag{language.Chinese}, Fallback: true},
+		{Path: "shop/order/status", Text: "Label", Missing: []language.Tag{language.English}, Fallback: false},
+		{Path: "shop/order/status/Values/closed", Text: "Label", Missing: []language.Tag{language.English, language.Chinese}, Fallback: false},
+	}, r.Gaps)
+
+	data, err := json.Marshal(r.Gaps[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Path":"shop/order","Text":"Description","Missing":["zh"],"Fallback":true}`, string(data))
+
+	err = r.Err()
+	var errs seederrors.DefinitionErrors
+	require.ErrorAs(t, err, &errs)
+	assert.Len(t, errs, 4)
+	assert.Equal(t, seederrors.NewDefinitionError(seederrors.DefinitionTranslation, "en,zh",
+		"shop", "order", "status", "Values", "closed", "Label"), errs[3])
+
+	assert.NoError(t, TranslationCoverage(d, nil).Err())
+}
+
+func TestTranslationCoveragePaths(t *testing.T) {
+	integer := func(name CodeName) *Field {
+		return &Field{Thing: Thing{Name: name}, FieldType: Integer, FieldTypeSetting: Int64Setting()}
+	}
+	d := must.V(NewDomain(Thing{Name: "shop"}, &Object{
+		Thing: Thing{Name: "order"},
+		FieldGroup: FieldGroup{
+			Fields: must.V(NewFields(integer("start"), integer("end"), &Field{
+				Thing:     Thing{Name: "tags"},
+				FieldType: List,
+				FieldTypeSetting: ListSetting{
+					ItemType: Enumeration,
+					ItemTypeSetting: EnumerationSetting{Values: []EnumerationValue{
+						{Thing: Thing{Name: "open"}},
+					}},
+					MaxLength: 3,
+				},
+			})),
+			Identities: []Identity{{Fields: []CodeName{"start"}, Ranges: []Range{{Start: "start", End: "end"}}}},
+			Ranges:     []Range{{Thing: Thing{Name: "period"}, Start: "start", End: "end"}},
+		},
+	}))
+	require.NoError(t, d.Validate())
+	var paths []string
+	for _, gap := range TranslationCoverage(d, []language.Tag{language.English}).Gaps {
+		paths = append(paths, gap.Path)
+	}
+	assert.Equal(t, []string{
+		"shop", "shop/order", "shop/order/start", "shop/order/end", "shop/order/tags",
+		"shop/order/tags/Item/Values/open", "shop/order/Identities/0", "shop/order/Identities/0/Ranges/0",
+		"shop/order/Ranges/period",
+	}, paths)
+}
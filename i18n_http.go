@@ -0,0 +1,109 @@
+package seed
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/puzpuzpuz/xsync/v2"
+	"golang.org/x/text/language"
+)
+
+// AcceptLanguagePicker creates a picker of the languages in an Accept-Language header, by their
+// q-values, then by their order in the header. Languages with q=0 and the wildcard "*" are ignored.
+// As with NewPicker, matches are picked by confidence first, so an exact match of a language with a
+// lower q-value is picked over a partial match of a language with a higher q-value.
+// If no languages matched, fallback is used, which is usually the default language of a domain.
+// If fallback is nil, English is used.
+//
+// Headers that can not be parsed give pickers that only use fallback.
+func AcceptLanguagePicker(header string, fallback *Picker) *Picker {
+	if fallback == nil {
+		fallback = _lastPicker
+	}
+	return NewPicker(acceptLanguages(header), fallback)
+}
+
+func acceptLanguages(header string) []language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return nil
+	}
+	wildcard := language.Make("mul") // "*" is parsed as multiple languages
+	out := tags[:0]
+	for _, tag := range tags {
+		if tag != language.Und && tag != wildcard {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// PickerCache caches pickers by Accept-Language headers, so that pickers and what they have
+// learned about languages are reused by requests of the same header.
+//
+// PickerCache is safe to use concurrently.
+type PickerCache struct {
+	fallback *Picker
+	maxSize  int
+	pickers  *xsync.MapOf[string, *Picker]
+}
+
+// DefaultPickerCacheSize is the default number of headers cached by a PickerCache.
+const DefaultPickerCacheSize = 1000
+
+// NewPickerCache creates a cache of pickers that use fallback, see AcceptLanguagePicker.
+// At most maxSize headers are cached, as headers are given by clients; if maxSize <= 0,
+// DefaultPickerCacheSize is used. Pickers of headers past the limit are created for each call.
+func NewPickerCache(fallback *Picker, maxSize int) *PickerCache {
+	if fallback == nil {
+		fallback = _lastPicker
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultPickerCacheSize
+	}
+	return &PickerCache{
+		fallback: fallback,
+		maxSize:  maxSize,
+		pickers:  xsync.NewMapOf[*Picker](),
+	}
+}
+
+// Get returns the picker of an Accept-Language header.
+func (c *PickerCache) Get(header string) *Picker {
+	header = strings.TrimSpace(header)
+	if p, ok := c.pickers.Load(header); ok {
+		return p
+	}
+	p := AcceptLanguagePicker(header, c.fallback)
+	if c.pickers.Size() >= c.maxSize {
+		return p
+	}
+	p, _ = c.pickers.LoadOrStore(header, p)
+	return p
+}
+
+// Middleware returns a handler that serves requests by next, with the picker of the Accept-Language
+// header of each request in its context, see PickerFromContext.
+func (c *PickerCache) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := c.Get(r.Header.Get("Accept-Language"))
+		next.ServeHTTP(w, r.WithContext(ContextWithPicker(r.Context(), p)))
+	})
+}
+
+type pickerContextKey struct{}
+
+// ContextWithPicker returns a copy of ctx with p, see PickerFromContext.
+func ContextWithPicker(ctx context.Context, p *Picker) context.Context {
+	return context.WithValue(ctx, pickerContextKey{}, p)
+}
+
+// PickerFromContext returns the picker in ctx, or the English picker of last resort if there is none.
+func PickerFromContext(ctx context.Context) *Picker {
+	p, ok := ctx.Value(pickerContextKey{}).(*Picker)
+	if !ok || p == nil {
+		return _lastPicker
+	}
+	return p
+}
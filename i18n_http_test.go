@@ -0,0 +1,62 @@
+package seed_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+
+	. "github.com/xiegeo/seed"
+)
+
+func TestAcceptLanguagePicker(t *testing.T) {
+	label := I18n[string]{language.English: "Order", language.Chinese: "订单", language.German: "Bestellung", language.French: "Commande"}
+	zhDefault := NewPicker([]language.Tag{language.Chinese}, nil)
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"de, fr;q=0.9", "Bestellung"},
+		{"de-CH, fr;q=0.9", "Commande"}, // an exact match is more confident
+		{"fr;q=0.2, de;q=0.9, en;q=0.5", "Bestellung"},
+		{"fr;q=0.2, en;q=0", "Commande"},
+		{"*;q=0.5, fr;q=0.1", "Commande"},
+		{"ja", "订单"},
+		{"", "订单"},
+		{"!!!", "订单"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, label.GetValue(AcceptLanguagePicker(tt.header, zhDefault), ""))
+		})
+	}
+	assert.Equal(t, "Order", label.GetValue(AcceptLanguagePicker("ja", nil), ""), "English without fallback")
+}
+
+func TestPickerCache(t *testing.T) {
+	c := NewPickerCache(NewPicker([]language.Tag{language.Chinese}, nil), 2)
+	zh := c.Get("zh-CN")
+	assert.Same(t, zh, c.Get(" zh-CN "))
+	assert.Same(t, c.Get("de"), c.Get("de"))
+	assert.NotSame(t, c.Get("fr"), c.Get("fr"), "not cached past the limit")
+
+	label := I18n[string]{language.English: "Order", language.Chinese: "订单"}
+	var got string
+	handler := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = label.GetValue(PickerFromContext(r.Context()), "")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "en-US,zh;q=0.5")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "Order", got)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "订单", got, "domain default")
+
+	require.NotNil(t, PickerFromContext(req.Context()))
+	assert.Equal(t, "Order", label.GetValue(PickerFromContext(req.Context()), ""), "last resort without middleware")
+}
@@ -0,0 +1,75 @@
+package seed
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// PseudoLanguage is the reserved pseudo-locale en-XA, for testing user interfaces.
+//
+// Pickers pick values for PseudoLanguage as for English. When it is the first preferred language of
+// a picker, strings picked by I18n.GetValue and errors rendered by ErrorLocalizer are
+// pseudo-localized: the best available text, usually English, is accented, lengthened by about 40%,
+// and marked by brackets, such as "[Öŕðéŕ one]". Text that is not pseudo-localized is hard-coded,
+// and text that is missing a bracket is clipped. Layouts and separators used by FormatValue are
+// kept as English, so formatted values can be parsed back.
+var PseudoLanguage = language.MustParse("en-XA")
+
+// isPseudo returns true if p requests PseudoLanguage.
+func (p *Picker) isPseudo() bool {
+	return p != nil && p.Language() == PseudoLanguage
+}
+
+// pseudoValue returns v pseudo-localized if it is a string.
+func pseudoValue[T any](v T) T {
+	s, ok := any(v).(string)
+	if !ok {
+		return v
+	}
+	return any(pseudoLocalize(s, false)).(T)
+}
+
+var _pseudoLetters = func() map[rune]rune {
+	from := "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	to := []rune("ÅƁÇÐÉƑĜĤÎĴĶĻṀÑÖÞǪŔŠŢÛṼŴẊÝŽåƀçðéƒĝĥîĵķļɱñöþǫŕšţûṽŵẋýž")
+	m := make(map[rune]rune, len(to))
+	for i, r := range from {
+		m[r] = to[i]
+	}
+	return m
+}()
+
+var _pseudoPadding = strings.Fields("one two three four five six seven eight nine ten")
+
+// pseudoLocalize accents the letters of s, lengthens it and marks it by brackets.
+// If keepVerbs is true, formatting verbs such as %s and %[2]d are kept as they are.
+func pseudoLocalize(s string, keepVerbs bool) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	inVerb := false
+	for _, r := range s {
+		switch {
+		case inVerb:
+			// verbs end with a letter, or the second % of %%
+			inVerb = !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '%')
+		case keepVerbs && r == '%':
+			inVerb = true
+		default:
+			if accented, ok := _pseudoLetters[r]; ok {
+				r = accented
+			}
+		}
+		b.WriteRune(r)
+	}
+	extra := (utf8.RuneCountInString(s)*2 + 4) / 5
+	for i := 0; extra > 0; i++ {
+		word := _pseudoPadding[i%len(_pseudoPadding)]
+		b.WriteByte(' ')
+		b.WriteString(word)
+		extra -= len(word) + 1
+	}
+	b.WriteByte(']')
+	return b.String()
+}
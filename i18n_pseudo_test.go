@@ -0,0 +1,70 @@
+package seed_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+
+	. "github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+func TestPseudoLanguage(t *testing.T) {
+	pseudo := NewPicker([]language.Tag{PseudoLanguage}, nil)
+	label := I18n[string]{language.English: "Order", language.Chinese: "订单"}
+	assert.Equal(t, "[Öŕðéŕ one]", label.GetValue(pseudo, ""))
+	assert.Equal(t, "[Öŕðéŕ one]", label.GetValue(AcceptLanguagePicker("en-XA, zh;q=0.5", nil), ""))
+	assert.Equal(t, "[Šĥîþþîñĝ ñöţé one two]", I18n[string]{language.AmericanEnglish: "Shipping note"}.GetValue(pseudo, ""))
+	assert.Equal(t, "[ýéš one]", I18n[string]{language.Chinese: "是"}.GetValue(pseudo, "yes"), "fallback")
+	assert.Equal(t, "订单", label.GetValue(NewPicker([]language.Tag{language.Chinese, PseudoLanguage}, nil), ""),
+		"only if most preferred")
+	assert.Equal(t, 42, I18n[int]{language.English: 42}.GetValue(pseudo, 0), "only strings")
+
+	d := translationTestDomain()
+	assert.Equal(t, `[ƒîéļð "[Ŵéîĝĥţ one]" îš ŕéǫûîŕéð one two three]`,
+		LocalizeError(seederrors.NewValueRequiredError("weight"), pseudo, d))
+	assert.Equal(t, `[ƒîéļð "nope" îš ŕéǫûîŕéð one two three]`,
+		LocalizeError(seederrors.NewValueRequiredError("nope"), pseudo, d), "names without labels are kept")
+}
+
+func TestPseudoLanguageFormat(t *testing.T) {
+	pseudo := NewPicker([]language.Tag{PseudoLanguage}, nil)
+	day := time.Date(2023, 3, 4, 0, 0, 0, 0, time.UTC)
+	color := EnumerationSetting{Values: []EnumerationValue{
+		{Thing: Thing{Name: "red", Label: I18n[string]{language.English: "Red"}}},
+		{Thing: Thing{Name: "green"}},
+	}}
+	tests := []struct {
+		name   string
+		f      *Field
+		v      any
+		want   string
+		parsed any
+	}{
+		{"date", &Field{Thing: Thing{Name: "day"}, FieldType: TimeStamp, FieldTypeSetting: TimeStampSetting{Scale: 24 * time.Hour}},
+			day, "Mar 4, 2023", day},
+		{"list", &Field{Thing: Thing{Name: "counts"}, FieldType: List, FieldTypeSetting: ListSetting{
+			MaxLength: 3, ItemType: Integer, ItemTypeSetting: Int64Setting(),
+		}}, []int64{1, 2, 3000}, "1, 2, 3,000", []any{int64(1), int64(2), int64(3000)}},
+		{"true", &Field{Thing: Thing{Name: "flag"}, FieldType: Boolean, FieldTypeSetting: BooleanSetting{}},
+			true, "[Ýéš one]", true},
+		{"false", &Field{Thing: Thing{Name: "flag"}, FieldType: Boolean, FieldTypeSetting: BooleanSetting{}},
+			false, "[Ñö one]", false},
+		{"enumeration", &Field{Thing: Thing{Name: "colors"}, FieldType: List, FieldTypeSetting: ListSetting{
+			MaxLength: 3, ItemType: Enumeration, ItemTypeSetting: color,
+		}}, []CodeName{"red", "green"}, "[Ŕéð one], [ĝŕééñ one]", []any{CodeName("red"), CodeName("green")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatValue(tt.f, tt.v, pseudo)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			parsed, err := ParseValue(tt.f, got, pseudo)
+			require.NoError(t, err)
+			assert.Equal(t, tt.parsed, parsed)
+		})
+	}
+}
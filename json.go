@@ -0,0 +1,387 @@
+package seed
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// JSON encoding of domain definitions.
+//
+// Domain, Object, FieldGroup and Field implement json.Marshaler and json.Unmarshaler, so a domain
+// can be kept in JSON files instead of Go source. Dictionaries are encoded as lists in logical order,
+// and decoded through NewObjects and NewFields, so naming rules are enforced on load.
+//
+// FieldTypeSetting is encoded as the setting value, the setting type to decode into is discriminated
+// by FieldType. Enumerated values, such as FieldType and RealStandard, are encoded by name.
+// time.Duration is encoded as a time.ParseDuration string. I18n values are keyed by BCP 47 tags.
+
+var (
+	_ json.Marshaler   = &Domain{}
+	_ json.Unmarshaler = &Domain{}
+	_ json.Marshaler   = &Object{}
+	_ json.Unmarshaler = &Object{}
+	_ json.Marshaler   = &FieldGroup{}
+	_ json.Unmarshaler = &FieldGroup{}
+	_ json.Marshaler   = &Field{}
+	_ json.Unmarshaler = &Field{}
+)
+
+type domainJSON struct {
+	Thing
+	Objects []*Object
+}
+
+func (d Domain) MarshalJSON() ([]byte, error) {
+	out := domainJSON{Thing: d.Thing}
+	if d.Objects != nil {
+		out.Objects = d.Objects.Values()
+	}
+	return json.Marshal(out)
+}
+
+func (d *Domain) UnmarshalJSON(data []byte) error {
+	var in domainJSON
+	err := json.Unmarshal(data, &in)
+	if err != nil {
+		return err
+	}
+	objects, err := NewObjects(in.Objects...)
+	if err != nil {
+		return seederrors.WithMessagef(err, "in domain %s", in.Name)
+	}
+	*d = Domain{
+		Thing:   in.Thing,
+		Objects: objects,
+	}
+	return nil
+}
+
+type fieldGroupJSON struct {
+	Fields     []*Field
+	Identities []Identity `json:",omitempty"`
+	Ranges     []Range    `json:",omitempty"`
+}
+
+func (g FieldGroup) toJSON() fieldGroupJSON {
+	out := fieldGroupJSON{
+		Identities: g.Identities,
+		Ranges:     g.Ranges,
+	}
+	if g.Fields != nil {
+		out.Fields = g.Fields.Values()
+	}
+	return out
+}
+
+func (g fieldGroupJSON) toFieldGroup() (FieldGroup, error) {
+	fields, err := NewFields(g.Fields...)
+	if err != nil {
+		return FieldGroup{}, err
+	}
+	return FieldGroup{
+		Fields:     fields,
+		Identities: g.Identities,
+		Ranges:     g.Ranges,
+	}, nil
+}
+
+func (g FieldGroup) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.toJSON())
+}
+
+func (g *FieldGroup) UnmarshalJSON(data []byte) error {
+	var in fieldGroupJSON
+	err := json.Unmarshal(data, &in)
+	if err != nil {
+		return err
+	}
+	*g, err = in.toFieldGroup()
+	return err
+}
+
+type objectJSON struct {
+	Thing
+	fieldGroupJSON
+}
+
+func (ob Object) MarshalJSON() ([]byte, error) {
+	return json.Marshal(objectJSON{
+		Thing:          ob.Thing,
+		fieldGroupJSON: ob.FieldGroup.toJSON(),
+	})
+}
+
+func (ob *Object) UnmarshalJSON(data []byte) error {
+	var in objectJSON
+	err := json.Unmarshal(data, &in)
+	if err != nil {
+		return err
+	}
+	group, err := in.toFieldGroup()
+	if err != nil {
+		return seederrors.WithMessagef(err, "in object %s", in.Name)
+	}
+	*ob = Object{
+		Thing:      in.Thing,
+		FieldGroup: group,
+	}
+	return nil
+}
+
+type fieldJSON struct {
+	Thing
+	FieldType        FieldType
+	FieldTypeSetting json.RawMessage
+	IsI18n           bool         `json:",omitempty"`
+	Nullable         bool         `json:",omitempty"`
+	Default          *defaultJSON `json:",omitempty"`
+}
+
+type defaultJSON struct {
+	Value     json.RawMessage  `json:",omitempty"`
+	Generator DefaultGenerator `json:",omitempty"`
+}
+
+func (f Field) MarshalJSON() ([]byte, error) {
+	setting, err := json.Marshal(f.FieldTypeSetting)
+	if err != nil {
+		return nil, err
+	}
+	out := fieldJSON{
+		Thing:            f.Thing,
+		FieldType:        f.FieldType,
+		FieldTypeSetting: setting,
+		IsI18n:           f.IsI18n,
+		Nullable:         f.Nullable,
+	}
+	if f.Default != nil {
+		out.Default = &defaultJSON{Generator: f.Default.Generator}
+		if f.Default.Value != nil {
+			out.Default.Value, err = json.Marshal(f.Default.Value)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return json.Marshal(out)
+}
+
+func (f *Field) UnmarshalJSON(data []byte) error {
+	var in fieldJSON
+	err := json.Unmarshal(data, &in)
+	if err != nil {
+		return err
+	}
+	setting, err := UnmarshalFieldTypeSetting(in.FieldType, in.FieldTypeSetting)
+	if err != nil {
+		return seederrors.WithMessagef(err, "in field %s", in.Name)
+	}
+	*f = Field{
+		Thing:            in.Thing,
+		FieldTypeSetting: setting,
+		FieldType:        in.FieldType,
+		IsI18n:           in.IsI18n,
+		Nullable:         in.Nullable,
+	}
+	if in.Default != nil {
+		f.Default = &Default{Generator: in.Default.Generator}
+		if len(in.Default.Value) > 0 {
+			f.Default.Value, err = unmarshalValue(in.FieldType, setting, in.Default.Value)
+			if err != nil {
+				return seederrors.WithMessagef(err, "in default of field %s", in.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// unmarshalValue decodes data to a value of field type t, only types that can be used as
+// default values are supported.
+func unmarshalValue(t FieldType, s FieldTypeSetting, data []byte) (any, error) {
+	switch t {
+	case String:
+		return unmarshalTo[string](data)
+	case Binary:
+		return unmarshalTo[[]byte](data)
+	case Boolean:
+		return unmarshalTo[bool](data)
+	case TimeStamp:
+		return unmarshalTo[time.Time](data)
+	case Integer:
+		i, err := unmarshalTo[*big.Int](data)
+		if err != nil || i == nil || !i.IsInt64() {
+			return i, err
+		}
+		return i.Int64(), nil
+	case Real:
+		if rs, ok := s.(RealSetting); ok && rs.Standard.IsDecimal() {
+			return unmarshalTo[decimal.Decimal](data)
+		}
+		return unmarshalTo[float64](data)
+	case Enumeration:
+		return unmarshalTo[CodeName](data)
+	}
+	return nil, seederrors.NewUnknownNameError("value of FieldType", t.String())
+}
+
+func unmarshalTo[T any](data []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
+
+// UnmarshalFieldTypeSetting decodes data to the FieldTypeSetting used by field type t.
+func UnmarshalFieldTypeSetting(t FieldType, data []byte) (FieldTypeSetting, error) {
+	switch t {
+	case String:
+		return unmarshalSetting[StringSetting](data)
+	case Binary:
+		return unmarshalSetting[BinarySetting](data)
+	case Boolean:
+		return unmarshalSetting[BooleanSetting](data)
+	case TimeStamp:
+		return unmarshalSetting[TimeStampSetting](data)
+	case Integer:
+		return unmarshalSetting[IntegerSetting](data)
+	case Real:
+		return unmarshalSetting[RealSetting](data)
+	case Reference:
+		return unmarshalSetting[ReferenceSetting](data)
+	case List:
+		return unmarshalSetting[ListSetting](data)
+	case Combination:
+		return unmarshalSetting[CombinationSetting](data)
+	case Enumeration:
+		return unmarshalSetting[EnumerationSetting](data)
+	}
+	return nil, seederrors.NewUnknownNameError("FieldType", t.String())
+}
+
+func unmarshalSetting[T FieldTypeSetting](data []byte) (FieldTypeSetting, error) {
+	var s T
+	if len(data) == 0 {
+		return s, nil
+	}
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+type timeStampSettingJSON TimeStampSetting // drops methods to avoid recursion
+
+func (s TimeStampSetting) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		timeStampSettingJSON
+		Scale string
+	}{
+		timeStampSettingJSON: timeStampSettingJSON(s),
+		Scale:                s.Scale.String(),
+	})
+}
+
+func (s *TimeStampSetting) UnmarshalJSON(data []byte) error {
+	var in struct {
+		timeStampSettingJSON
+		Scale string
+	}
+	err := json.Unmarshal(data, &in)
+	if err != nil {
+		return err
+	}
+	*s = TimeStampSetting(in.timeStampSettingJSON)
+	if in.Scale != "" {
+		s.Scale, err = time.ParseDuration(in.Scale)
+	}
+	return err
+}
+
+type realSettingJSON RealSetting // drops methods to avoid recursion
+
+// MarshalJSON encodes infinite float bounds as not set, since they have the same meaning
+// and are not supported by JSON.
+func (s RealSetting) MarshalJSON() ([]byte, error) {
+	if s.MinFloat != nil && math.IsInf(*s.MinFloat, -1) {
+		s.MinFloat = nil
+	}
+	if s.MaxFloat != nil && math.IsInf(*s.MaxFloat, 1) {
+		s.MaxFloat = nil
+	}
+	return json.Marshal(realSettingJSON(s))
+}
+
+type listSettingJSON ListSetting // drops methods to avoid recursion
+
+func (s *ListSetting) UnmarshalJSON(data []byte) error {
+	var in struct {
+		listSettingJSON
+		ItemTypeSetting json.RawMessage
+	}
+	err := json.Unmarshal(data, &in)
+	if err != nil {
+		return err
+	}
+	*s = ListSetting(in.listSettingJSON)
+	s.ItemTypeSetting, err = UnmarshalFieldTypeSetting(s.ItemType, in.ItemTypeSetting)
+	return err
+}
+
+func (f FieldType) MarshalText() ([]byte, error) {
+	return marshalEnum("FieldType", _fieldTypeStringer, f)
+}
+
+func (f *FieldType) UnmarshalText(text []byte) error {
+	return unmarshalEnum("FieldType", _fieldTypeStringer, f, text)
+}
+
+func (s RealStandard) MarshalText() ([]byte, error) {
+	return marshalEnum("RealStandard", _realStandardStringer, s)
+}
+
+func (s *RealStandard) UnmarshalText(text []byte) error {
+	return unmarshalEnum("RealStandard", _realStandardStringer, s, text)
+}
+
+func (g DefaultGenerator) MarshalText() ([]byte, error) {
+	return marshalEnum("DefaultGenerator", _defaultGeneratorStringer, g)
+}
+
+func (g *DefaultGenerator) UnmarshalText(text []byte) error {
+	return unmarshalEnum("DefaultGenerator", _defaultGeneratorStringer, g, text)
+}
+
+func (a ReferenceTrackingAction) MarshalText() ([]byte, error) {
+	return marshalEnum("ReferenceTrackingAction", _referenceTrackingActionStringer, a)
+}
+
+func (a *ReferenceTrackingAction) UnmarshalText(text []byte) error {
+	return unmarshalEnum("ReferenceTrackingAction", _referenceTrackingActionStringer, a, text)
+}
+
+type enum interface {
+	~int8 | ~uint8
+}
+
+func marshalEnum[E enum](of string, names []string, e E) ([]byte, error) {
+	if int(e) < 0 || int(e) >= len(names) {
+		return nil, seederrors.NewSystemError("%s(%d) out of range[0,%d)", of, e, len(names))
+	}
+	return []byte(names[e]), nil
+}
+
+func unmarshalEnum[E enum](of string, names []string, e *E, text []byte) error {
+	for i, name := range names {
+		if name == string(text) {
+			*e = E(i)
+			return nil
+		}
+	}
+	return seederrors.NewUnknownNameError(of, string(text))
+}
@@ -0,0 +1,145 @@
+package seed_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
+	"golang.org/x/text/language"
+
+	. "github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/demo/testdomain"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+func jsonTestDomain() *Domain {
+	ob := &Object{
+		Thing: Thing{
+			Name:  "all_settings",
+			Label: I18n[string]{language.English: "All Settings", language.Chinese: "所有设置"},
+		},
+		FieldGroup: FieldGroup{
+			Fields: must.V(NewFields(
+				testdomain.TextLineField(),
+				testdomain.WithTimeZone(testdomain.Date()),
+				testdomain.Float64(),
+				&Field{
+					Thing:     Thing{Name: "big"},
+					FieldType: Integer,
+					FieldTypeSetting: IntegerSetting{
+						Min:  big.NewInt(0),
+						Max:  new(big.Int).Lsh(big.NewInt(1), 100),
+						Unit: &Unit{Thing: Thing{Name: "gram"}, Symble: "g"},
+					},
+					Nullable: true,
+					Default:  &Default{Value: int64(5)},
+				},
+				&Field{
+					Thing:     Thing{Name: "custom_real"},
+					FieldType: Real,
+					FieldTypeSetting: RealSetting{
+						Standard:    CustomReal,
+						Base:        10,
+						MinMantissa: big.NewInt(-999),
+						MaxMantissa: big.NewInt(999),
+						MinExponent: new(int64),
+						MaxExponent: new(int64),
+					},
+				},
+				&Field{
+					Thing:     Thing{Name: "ref"},
+					FieldType: Reference,
+					FieldTypeSetting: ReferenceSetting{
+						Object: "level_0",
+						ReferenceTrackingOption: ReferenceTrackingOption{
+							OnUpdate: ActionCascade,
+							OnDelete: ActionSetNull,
+						},
+					},
+				},
+				testdomain.ListOf(testdomain.Bool(), ListSetting{MaxLength: 3, IsOrdered: true}),
+				withDefault(testdomain.Enumeration(), Default{Value: CodeName("draft")}),
+				withDefault(testdomain.DateTimeSec(), Default{Generator: GenerateNow}),
+				&Field{
+					Thing:     Thing{Name: "combination"},
+					FieldType: Combination,
+					FieldTypeSetting: CombinationSetting{
+						Fields:     must.V(NewFields(testdomain.DateTimeSec(), testdomain.DateTimeMill())),
+						Identities: []Identity{{Fields: []CodeName{testdomain.DateTimeSec().Name}}},
+						Ranges: []Range{{
+							Start: testdomain.DateTimeSec().Name,
+							End:   testdomain.DateTimeMill().Name,
+						}},
+					},
+				},
+			)),
+			Identities: []Identity{{
+				Thing:  Thing{Name: "id"},
+				Fields: []CodeName{testdomain.TextLineField().Name},
+			}},
+		},
+	}
+	return must.V(NewDomain(Thing{Name: "json_test"}, testdomain.ObjLevel0(), ob))
+}
+
+func TestDomainJSON(t *testing.T) {
+	domain := jsonTestDomain()
+	require.NoError(t, domain.Validate())
+	data, err := json.Marshal(domain)
+	require.NoError(t, err)
+
+	var decoded Domain
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.NoError(t, decoded.Validate())
+	data2, err := json.Marshal(&decoded)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(data2))
+
+	assert.Equal(t, []CodeName{"level_0", "all_settings"}, names(decoded.Objects.Values()))
+	ob, ok := decoded.Objects.Get("all_settings")
+	require.True(t, ok)
+	assert.Equal(t, "所有设置", ob.Label[language.Chinese])
+	assert.Equal(t, names(jsonTestDomain().Objects.Values()[1].Fields.Values()), names(ob.Fields.Values()))
+	for _, f := range jsonTestDomain().Objects.Values()[1].Fields.Values() {
+		got, _ := ob.Fields.Get(f.Name)
+		if f.FieldType == Combination {
+			continue // combinations hold dictionaries, which are checked by round trip.
+		}
+		assert.Equal(t, f, got)
+	}
+}
+
+func TestFieldJSON(t *testing.T) {
+	data, err := json.Marshal(testdomain.DateTimeSec())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"Name":"datetime_sec_9999",
+		"Label":{"en":"Date and Time"},
+		"FieldType":"TimeStamp",
+		"FieldTypeSetting":{"Min":"0001-01-01T00:00:00Z","Max":"9999-12-31T23:59:59.999999999Z","Scale":"1s","WithTimeZoneOffset":false}
+	}`, string(data))
+
+	var f Field
+	err = json.Unmarshal([]byte(`{"Name":"a","FieldType":"Unknown"}`), &f)
+	assert.ErrorAs(t, err, &seederrors.UnknownNameError{})
+
+	var ob Object
+	err = json.Unmarshal([]byte(`{"Name":"ob","Fields":[{"Name":"a_b","FieldType":"Boolean"},{"Name":"aB","FieldType":"Boolean"}]}`), &ob)
+	assert.ErrorAs(t, err, &seederrors.NameRepeatedError{}, "naming rules are enforced")
+}
+
+func withDefault(f *Field, d Default) *Field {
+	f.Default = &d
+	return f
+}
+
+func names[T ThingGetter](vs []T) []CodeName {
+	out := make([]CodeName, len(vs))
+	for i, v := range vs {
+		out[i] = v.GetName()
+	}
+	return out
+}
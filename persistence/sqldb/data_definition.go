@@ -13,6 +13,10 @@ func (db *DB) AddDomain(ctx context.Context, d seed.DomainGetter) error {
 	if _, ok := db.domains[d.GetName()]; ok {
 		return seederrors.NewCodeNameExistsError(d.GetName(), seederrors.ThingTypeDomain, "")
 	}
+	err := seed.ValidateDomain(d)
+	if err != nil {
+		return err
+	}
 	domainInfo, err := db.domainInfoFromDomain(d)
 	if err != nil {
 		return err
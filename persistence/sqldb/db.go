@@ -34,6 +34,10 @@ type DBOption struct {
 
 	TableOption         string // Default table option
 	TableOptionNoAutoID string // The table option to use in addition if PrimaryKeys does not use auto increment
+
+	// TimeNow returns the expression of the current UTC time formatted by a Go time layout, used as the
+	// column default of seed.GenerateNow. If it is nil, generated time stamps are only applied to inserted rows.
+	TimeNow func(layout string) (Expression, bool)
 }
 
 func newDefaultOption() *DBOption {
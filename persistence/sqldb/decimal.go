@@ -0,0 +1,138 @@
+package sqldb
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// Decimals are stored as text with an order preserving encoding, so that values are exact and
+// comparisons of text give the same order as comparisons of values:
+//
+//   - zero is "1".
+//   - positive values are "2", then the exponent as 3 digits, then the digits.
+//   - negative values are "0", then the 9's complement of the exponent and digits, then "~".
+//
+// Digits are normalized, so that value = 0.digits * 10^exponent, where digits do not start or end in 0.
+// Exponent is stored with an offset of decimalExponentOffset.
+const (
+	decimalNegative       = '0'
+	decimalZero           = '1'
+	decimalPositive       = '2'
+	decimalTerminator     = '~' // sorts after all digits
+	decimalExponentDigits = 3
+	decimalExponentOffset = 500
+)
+
+// decimalKeyCodePoints returns the max size of an encoded decimal with up to digits number of digits.
+func decimalKeyCodePoints(digits int64) int64 {
+	return 1 + decimalExponentDigits + digits + 1
+}
+
+func encodeDecimal(d decimal.Decimal) (string, error) {
+	sign := d.Sign()
+	if sign == 0 {
+		return string(decimalZero), nil
+	}
+	digits := new(big.Int).Abs(d.Coefficient()).String()
+	trimmed := strings.TrimRight(digits, "0")
+	exponent := int64(d.Exponent()) + int64(len(digits)) + decimalExponentOffset
+	if exponent < 0 || exponent >= 1000 {
+		return "", seederrors.NewSystemError("decimal %s is out of range for encoding", d)
+	}
+	exponentString := leftPad(big.NewInt(exponent).String(), decimalExponentDigits)
+	if sign > 0 {
+		return string(decimalPositive) + exponentString + trimmed, nil
+	}
+	return string(decimalNegative) + complement9(exponentString) + complement9(trimmed) + string(decimalTerminator), nil
+}
+
+func decodeDecimal(s string) (decimal.Decimal, error) {
+	if len(s) == 0 {
+		return decimal.Decimal{}, seederrors.NewSystemError("empty decimal encoding")
+	}
+	body := s[1:]
+	switch s[0] {
+	case decimalZero:
+		return decimal.Decimal{}, nil
+	case decimalPositive:
+	case decimalNegative:
+		body = complement9(strings.TrimSuffix(body, string(decimalTerminator)))
+	default:
+		return decimal.Decimal{}, seederrors.NewSystemError("decimal encoding %s has unknown sign", s)
+	}
+	if len(body) <= decimalExponentDigits {
+		return decimal.Decimal{}, seederrors.NewSystemError("decimal encoding %s is too short", s)
+	}
+	exponent, ok := new(big.Int).SetString(body[:decimalExponentDigits], 10)
+	if !ok {
+		return decimal.Decimal{}, seederrors.NewSystemError("decimal encoding %s has bad exponent", s)
+	}
+	digits := body[decimalExponentDigits:]
+	coefficient, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return decimal.Decimal{}, seederrors.NewSystemError("decimal encoding %s has bad digits", s)
+	}
+	if s[0] == decimalNegative {
+		coefficient.Neg(coefficient)
+	}
+	return decimal.NewFromBigInt(coefficient, int32(exponent.Int64()-decimalExponentOffset-int64(len(digits)))), nil
+}
+
+func leftPad(s string, size int) string {
+	if len(s) >= size {
+		return s
+	}
+	return strings.Repeat("0", size-len(s)) + s
+}
+
+func complement9(s string) string {
+	out := []byte(s)
+	for i, c := range out {
+		out[i] = '9' - c + '0'
+	}
+	return string(out)
+}
+
+func (builder *fieldInfoBuilder) decimalFailback(f *seed.Field, setting seed.RealSetting) (*fieldInfo, error) {
+	if !setting.Valid() {
+		return nil, seederrors.NewFieldNotSupportedError(f.FieldType.String(), f.Name, setting.Standard.String(), "Standard")
+	}
+	digits := int64(len(new(big.Int).Abs(setting.MinMantissa).String()))
+	if maxDigits := int64(len(setting.MaxMantissa.String())); maxDigits > digits {
+		digits = maxDigits
+	}
+	textField := *f
+	textField.FieldType = seed.String
+	textField.FieldTypeSetting = seed.StringSetting{
+		MinCodePoints: 1,
+		MaxCodePoints: decimalKeyCodePoints(digits),
+		IsSingleLine:  true,
+	}
+	fi, err := builder.generateFieldInfoSub(&textField)
+	if err != nil {
+		return nil, err
+	}
+	fi.WarpEncoder(func(v any) (any, error) {
+		vt, ok := v.(decimal.Decimal)
+		if !ok {
+			return nil, seederrors.NewSystemError("encoder expect decimal.Decimal but got %T", v)
+		}
+		if !setting.FitsDecimal(vt) {
+			return nil, seederrors.NewSystemError("decimal %s can not be stored exactly in field %s", vt, f.Name)
+		}
+		return encodeDecimal(vt)
+	})
+	fi.WarpDecoder(func(a any) (any, error) {
+		vt, ok := a.(string)
+		if !ok {
+			return nil, seederrors.NewSystemError("decoder expected string but got %T", a)
+		}
+		return decodeDecimal(vt)
+	})
+	return fi, nil
+}
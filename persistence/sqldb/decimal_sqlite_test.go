@@ -0,0 +1,50 @@
+package sqldb_test
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
+
+	"github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/demo/testdomain"
+	"github.com/xiegeo/seed/seedfake"
+)
+
+func TestDecimalSqlite3(t *testing.T) {
+	ctx := context.Background()
+	field := testdomain.Decimal64()
+	rank := testdomain.JSInteger()
+	rawDB, db, ob := newSqlite3Object(t, ctx, "decimals", field, rank)
+	gen := seedfake.NewValueGen(seedfake.NewMinMaxFlat(rand.NewSource(0), 1, 1, 5))
+	values := must.V(gen.ValuesForObject(ob, 50))
+	decimals := make([]decimal.Decimal, len(values))
+	for i, v := range values {
+		decimals[i] = v[field.Name].(decimal.Decimal)
+	}
+	sort.Slice(decimals, func(i, j int) bool { return decimals[i].LessThan(decimals[j]) })
+	for _, v := range values {
+		v[rank.Name] = int64(sort.Search(len(decimals), func(i int) bool {
+			return decimals[i].GreaterThanOrEqual(v[field.Name].(decimal.Decimal))
+		}))
+	}
+	require.NoError(t, db.InsertObjects(ctx, map[seed.CodeName]any{ob.Name: values}))
+
+	rows, err := rawDB.QueryContext(ctx, "SELECT integer_js FROM test_decimals ORDER BY decimal_64")
+	require.NoError(t, err)
+	defer rows.Close()
+	var got []int64
+	for rows.Next() {
+		var i int64
+		require.NoError(t, rows.Scan(&i))
+		got = append(got, i)
+	}
+	require.NoError(t, rows.Err())
+	require.Len(t, got, len(values))
+	assert.True(t, sort.SliceIsSorted(got, func(i, j int) bool { return got[i] < got[j] }), "%v", got)
+}
@@ -0,0 +1,28 @@
+package sqldb
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecimalEncoding(t *testing.T) {
+	values := []string{
+		"-1e385", "-12345", "-12340", "-1234", "-0.51", "-0.5", "-1e-397",
+		"0", "1e-397", "0.5", "0.51", "1234", "12340", "12345", "9999999999999999e369",
+	}
+	keys := make([]string, len(values))
+	for i, v := range values {
+		d := decimal.RequireFromString(v)
+		key, err := encodeDecimal(d)
+		require.NoError(t, err, v)
+		keys[i] = key
+		decoded, err := decodeDecimal(key)
+		require.NoError(t, err, v)
+		assert.True(t, d.Equal(decoded), "%s decoded as %s", v, decoded)
+	}
+	assert.True(t, sort.StringsAreSorted(keys), "%v", keys)
+}
@@ -0,0 +1,111 @@
+package sqldb
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// setColumnDefaults adds default values of the field to column definitions.
+// Generated defaults, such as seed.GenerateNow, are added if the database can generate them,
+// see timeNowDefaults. Otherwise, they are only applied when rows are inserted.
+func (f *fieldInfo) setColumnDefaults(option *DBOption) error {
+	if f.Default == nil {
+		return nil
+	}
+	var values []any
+	var err error
+	generated := map[int]Expression{}
+	switch f.Default.Generator {
+	case seed.GeneratorUnset:
+		values, err = f.Encoder()(f.Default.Value)
+	case seed.GenerateNow:
+		values, generated, err = f.timeNowDefaults(option)
+	default:
+		return nil
+	}
+	if err != nil {
+		return seederrors.WithMessagef(err, "encode default of field %s", f.Name)
+	}
+	if values == nil {
+		return nil
+	}
+	if len(values) != len(f.cols) {
+		return seederrors.NewSystemError("can not set %d default values to %d columns", len(values), len(f.cols))
+	}
+	for i, v := range values {
+		literal, ok := generated[i]
+		if !ok {
+			literal, err = sqlLiteral(v)
+			if err != nil {
+				return seederrors.WithMessagef(err, "default of field %s", f.Name)
+			}
+		}
+		f.cols[i].Constraint.Default = &literal
+	}
+	return nil
+}
+
+// _timeNowMarker is encoded to find the columns of time stamps that are formatted as strings.
+var _timeNowMarker = time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+
+// timeNowDefaults returns the encoded values of a time stamp in UTC, with the expressions of option.TimeNow
+// for columns of formatted time. Values are nil if the database can not generate the time at the scale of
+// the field, which must be a day, a second, or divide a millisecond.
+func (f *fieldInfo) timeNowDefaults(option *DBOption) ([]any, map[int]Expression, error) {
+	s, ok := f.FieldTypeSetting.(seed.TimeStampSetting)
+	if !ok || option.TimeNow == nil || s.Scale <= 0 ||
+		s.Scale != day && s.Scale != time.Second && time.Millisecond%s.Scale != 0 {
+		return nil, nil, nil
+	}
+	layout := utcTimeLayoutForScale(s.Scale)
+	now, ok := option.TimeNow(layout)
+	if !ok {
+		return nil, nil, nil
+	}
+	values, err := f.Encoder()(_timeNowMarker)
+	if err != nil {
+		return nil, nil, err
+	}
+	generated := map[int]Expression{}
+	for i, v := range values {
+		if v == _timeNowMarker.Format(layout) {
+			generated[i] = now
+		}
+	}
+	if len(generated) == 0 {
+		return nil, nil, nil
+	}
+	return values, generated, nil
+}
+
+// sqlLiteral formats an encoded column value as a SQL literal.
+func sqlLiteral(v any) (Expression, error) {
+	switch vt := v.(type) {
+	case nil:
+		return ValueLiteral("NULL"), nil
+	case string:
+		return ValueLiteral("'" + strings.ReplaceAll(vt, "'", "''") + "'"), nil
+	case []byte:
+		return ValueLiteral("X'" + hex.EncodeToString(vt) + "'"), nil
+	case bool:
+		if vt {
+			return ValueLiteral("1"), nil
+		}
+		return ValueLiteral("0"), nil
+	case int:
+		return ValueLiteral(strconv.Itoa(vt)), nil
+	case int64:
+		return ValueLiteral(strconv.FormatInt(vt, 10)), nil
+	case *big.Int:
+		return ValueLiteral(vt.String()), nil
+	case float64:
+		return ValueLiteral(strconv.FormatFloat(vt, 'g', -1, 64)), nil
+	}
+	return Expression{}, seederrors.NewSystemError("sqlLiteral type %T not supported", v)
+}
@@ -0,0 +1,122 @@
+package sqldb_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
+
+	"github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/demo/testdomain"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestDefaultsSqlite3(t *testing.T) {
+	ctx := context.Background()
+	count := testdomain.JSInteger()
+	count.Default = &seed.Default{Value: int64(7)}
+	status := testdomain.Enumeration()
+	status.Default = &seed.Default{Value: seed.CodeName("draft")}
+	created := testdomain.DateTimeSec()
+	created.Default = &seed.Default{Generator: seed.GenerateNow}
+	note := testdomain.TextLineField()
+	note.Nullable = true
+	note.Default = &seed.Default{Value: "it's new"}
+	rawDB, db, ob := newSqlite3Object(t, ctx, "defaults", count, status, created, note)
+
+	before := time.Now().Add(-time.Second)
+	require.NoError(t, db.InsertObjects(ctx, map[seed.CodeName]any{ob.Name: []map[seed.CodeName]any{
+		{},
+		{note.Name: nil}, // explicit null is kept for nullable fields
+	}}))
+	rows, err := rawDB.QueryContext(ctx, "SELECT integer_js, status, datetime_sec_9999, text_10 FROM test_defaults")
+	require.NoError(t, err)
+	defer rows.Close()
+	var notes []sql.NullString
+	for rows.Next() {
+		var (
+			i       int64
+			s, date string
+			n       sql.NullString
+		)
+		require.NoError(t, rows.Scan(&i, &s, &date, &n))
+		assert.Equal(t, int64(7), i)
+		assert.Equal(t, "draft", s)
+		assert.False(t, must.V(time.Parse("2006-01-02T15:04:05", date)).Before(before.UTC().Truncate(time.Second)), date)
+		notes = append(notes, n)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []sql.NullString{{String: "it's new", Valid: true}, {}}, notes)
+
+	_, err = rawDB.ExecContext(ctx, "INSERT INTO test_defaults (datetime_sec_9999) VALUES ('2000-01-01T00:00:00')")
+	require.NoError(t, err, "literal defaults are part of the table definition")
+	var (
+		i int64
+		s string
+	)
+	require.NoError(t, rawDB.QueryRowContext(ctx,
+		"SELECT integer_js, status FROM test_defaults WHERE datetime_sec_9999 = '2000-01-01T00:00:00'").Scan(&i, &s))
+	assert.Equal(t, int64(7), i)
+	assert.Equal(t, "draft", s)
+}
+
+func TestGeneratedDefaultsSqlite3(t *testing.T) {
+	ctx := context.Background()
+	withTZ := testdomain.DateTimeMill()
+	withTZ.Name = "datetime_mill_tz"
+	setting := withTZ.FieldTypeSetting.(seed.TimeStampSetting)
+	setting.WithTimeZoneOffset = true
+	withTZ.FieldTypeSetting = setting
+	minute := testdomain.DateTimeSec()
+	minute.Name = "datetime_minute"
+	setting = minute.FieldTypeSetting.(seed.TimeStampSetting)
+	setting.Scale = time.Minute
+	minute.FieldTypeSetting = setting
+	minute.Nullable = true
+	fields := []*seed.Field{
+		testdomain.Date(), testdomain.DateTimeSec(), testdomain.DateTimeMill(),
+		testdomain.DateTimeMicro(), testdomain.DateTimeNano(), withTZ, minute,
+	}
+	for _, f := range fields {
+		f.Default = &seed.Default{Generator: seed.GenerateNow}
+	}
+	rawDB, db, ob := newSqlite3Object(t, ctx, "now", append(fields, testdomain.JSInteger())...)
+
+	before := time.Now().UTC()
+	_, err := rawDB.ExecContext(ctx, "INSERT INTO test_now (integer_js) VALUES (1)")
+	require.NoError(t, err, "generated defaults are part of the table definition")
+	require.NoError(t, db.InsertObjects(ctx, map[seed.CodeName]any{ob.Name: []map[seed.CodeName]any{
+		{"integer_js": int64(2)},
+	}}))
+	after := time.Now().UTC()
+
+	rows, err := rawDB.QueryContext(ctx, `SELECT date_9999, datetime_sec_9999, datetime_mill_9999, datetime_micro_9999,
+		datetime_nano_9999, datetime_mill_tz, datetime_mill_tz_tz, datetime_minute FROM test_now ORDER BY integer_js`)
+	require.NoError(t, err)
+	defer rows.Close()
+	var got [][]sql.NullString
+	for rows.Next() {
+		row := make([]sql.NullString, 8)
+		require.NoError(t, rows.Scan(&row[0], &row[1], &row[2], &row[3], &row[4], &row[5], &row[6], &row[7]))
+		got = append(got, row)
+	}
+	require.NoError(t, rows.Err())
+	require.Len(t, got, 2)
+	generated, inserted := got[0], got[1]
+	assert.Equal(t, before.Format("2006-01-02"), generated[0].String)
+	for i := 1; i < 6; i++ {
+		assert.Len(t, generated[i].String, len(inserted[i].String), "same format as values inserted by sqldb")
+		value, err := time.Parse("2006-01-02T15:04:05", generated[i].String)
+		require.NoError(t, err)
+		assert.False(t, value.Before(before.Truncate(time.Second)) || value.After(after),
+			"%s not in [%s, %s]", value, before, after)
+	}
+	assert.Equal(t, "0", generated[6].String)
+	assert.False(t, generated[7].Valid, "minutes can not be generated by the database")
+	assert.True(t, inserted[7].Valid)
+}
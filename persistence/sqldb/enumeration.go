@@ -0,0 +1,67 @@
+package sqldb
+
+import (
+	"github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// enumerationFieldInfo stores enumeration values by name in a string column,
+// with a check that only listed names are allowed.
+func (builder *fieldInfoBuilder) enumerationFieldInfo(f *seed.Field, setting seed.EnumerationSetting) (*fieldInfo, error) {
+	if len(setting.Values) == 0 {
+		return nil, seederrors.NewFieldNotSupportedError(f.FieldType.String(), f.Name, "Values")
+	}
+	var maxCodePoints int64
+	names := make([]Expression, len(setting.Values))
+	for i, v := range setting.Values {
+		if l := int64(len(v.Name)); l > maxCodePoints {
+			maxCodePoints = l
+		}
+		names[i] = ValueLiteral("'" + string(v.Name) + "'") // code names do not need escaping
+	}
+	textField := *f
+	textField.FieldType = seed.String
+	textField.FieldTypeSetting = seed.StringSetting{
+		MinCodePoints: 1,
+		MaxCodePoints: maxCodePoints,
+		IsSingleLine:  true,
+	}
+	fi, err := builder.generateFieldInfoSub(&textField)
+	if err != nil {
+		return nil, err
+	}
+	if len(fi.cols) != 1 {
+		return nil, seederrors.NewSystemError("enumeration field %s expected 1 column, got %d", f.Name, len(fi.cols))
+	}
+	fi.checks = append(fi.checks, Expression{
+		Type: BinaryExpression,
+		A:    "IN",
+		Expressions: []Expression{
+			ValueLiteral(fi.cols[0].Name),
+			{Type: ListExpression, Expressions: names},
+		},
+	})
+	fi.WarpEncoder(func(v any) (any, error) {
+		var name seed.CodeName
+		switch vt := v.(type) {
+		case seed.CodeName:
+			name = vt
+		case string:
+			name = seed.CodeName(vt)
+		default:
+			return nil, seederrors.NewSystemError("encoder expect seed.CodeName but got %T", v)
+		}
+		if !setting.Has(name) {
+			return nil, seederrors.NewSystemError("%s is not a value of enumeration field %s", name, f.Name)
+		}
+		return string(name), nil
+	})
+	fi.WarpDecoder(func(a any) (any, error) {
+		vt, ok := a.(string)
+		if !ok {
+			return nil, seederrors.NewSystemError("decoder expected string but got %T", a)
+		}
+		return seed.CodeName(vt), nil
+	})
+	return fi, nil
+}
@@ -0,0 +1,45 @@
+package sqldb_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
+
+	"github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/demo/testdomain"
+	"github.com/xiegeo/seed/seedfake"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestEnumerationSqlite3(t *testing.T) {
+	ctx := context.Background()
+	field := testdomain.Enumeration()
+	rawDB, db, ob := newSqlite3Object(t, ctx, "enums", field)
+	gen := seedfake.NewValueGen(seedfake.NewMinMaxFlat(rand.NewSource(0), 1, 1, 5))
+	values := must.V(gen.ValuesForObject(ob, 20))
+	require.NoError(t, db.InsertObjects(ctx, map[seed.CodeName]any{ob.Name: values}))
+
+	setting := field.FieldTypeSetting.(seed.EnumerationSetting)
+	rows, err := rawDB.QueryContext(ctx, "SELECT status FROM test_enums")
+	require.NoError(t, err)
+	defer rows.Close()
+	count := 0
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		assert.True(t, setting.Has(seed.CodeName(name)), name)
+		count++
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, len(values), count)
+
+	err = db.InsertObjects(ctx, map[seed.CodeName]any{ob.Name: []map[seed.CodeName]any{{field.Name: "deleted"}}})
+	assert.Error(t, err, "encoder rejects unknown values")
+	_, err = rawDB.ExecContext(ctx, "INSERT INTO test_enums (status) VALUES ('deleted')")
+	assert.Error(t, err, "check constraint rejects unknown values")
+}
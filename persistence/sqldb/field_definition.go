@@ -65,6 +65,10 @@ func (builder *fieldInfoBuilder) generateFieldInfo(f *seed.Field) (*fieldInfo, e
 		return nil, err
 	}
 	fi.Field = *f
+	err = fi.setColumnDefaults(builder.db.option)
+	if err != nil {
+		return nil, err
+	}
 	return fi, nil
 }
 
@@ -88,8 +92,14 @@ func (builder *fieldInfoBuilder) generateFieldInfoSub(f *seed.Field) (*fieldInfo
 		return fd, err
 	case seed.TimeStampSetting:
 		return builder.timeStampFailback(f, setting)
+	case seed.RealSetting:
+		if setting.Standard.IsDecimal() {
+			return builder.decimalFailback(f, setting)
+		}
 	case seed.ListSetting:
 		return builder.listFieldInfo(f, setting)
+	case seed.EnumerationSetting:
+		return builder.enumerationFieldInfo(f, setting)
 	case nil:
 		return nil, seederrors.NewSystemError("FieldTypeSetting not set in field %s", f.Name)
 	}
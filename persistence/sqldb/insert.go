@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/xiegeo/seed"
 	"github.com/xiegeo/seed/seederrors"
 )
 
 // InsertObjects insert data keyed by object code name. If value is a slice, it's treated as a list of values.
+// Values can be map[string|seed.CodeName]any, seed.ObjectValue, or structs described by seed.ObjectFromStruct.
 // All inserts must be done or none at all.
 func (db *DB) InsertObjects(ctx context.Context, v map[seed.CodeName]any) error {
 	return db.InsertDomainObjects(ctx, db.defaultDomain, v)
@@ -52,6 +54,7 @@ func (db *DB) InsertDomainObjects(ctx context.Context, domain *domainInfo, v map
 type batchTables struct {
 	domain domainInfo
 	tables map[string]batchRows
+	now    time.Time // used to generate defaults, so that all values in a batch share the same time.
 }
 
 type batchRows struct {
@@ -83,6 +86,7 @@ func newBatchTables(domain *domainInfo) *batchTables {
 	return &batchTables{
 		domain: *domain,
 		tables: make(map[string]batchRows),
+		now:    time.Now(),
 	}
 }
 
@@ -115,7 +119,7 @@ func (b *batchTables) appendValue(obInfo *objectInfo, dataValue reflect.Value) e
 	}
 	switch dataValue.Kind() {
 	default:
-		return seederrors.NewSystemError("Kind %s in input of type %s not handled, use map for single data and slice for batch, structs will be supported in the future", dataValue.Kind(), dataValue.Type())
+		return seederrors.NewSystemError("Kind %s in input of type %s not handled, use map or struct for single data and slice for batch", dataValue.Kind(), dataValue.Type())
 	case reflect.Array, reflect.Slice:
 		for i := 0; i < dataValue.Len(); i++ {
 			err := b.appendValue(obInfo, dataValue.Index(i))
@@ -124,6 +128,15 @@ func (b *batchTables) appendValue(obInfo *objectInfo, dataValue reflect.Value) e
 			}
 		}
 		return nil
+	case reflect.Struct:
+		if ob, ok := dataValue.Interface().(seed.ObjectValue); ok {
+			return appendMapValue(b, obInfo, ob.Map())
+		}
+		values, err := seed.ValuesFromStruct(dataValue.Interface())
+		if err != nil {
+			return err
+		}
+		return appendMapValue(b, obInfo, values)
 	case reflect.Map:
 		switch mapTyped := dataValue.Interface().(type) {
 		case map[string]any:
@@ -139,7 +152,12 @@ func appendMapValue[K ~string](b *batchTables, obInfo *objectInfo, m map[K]any)
 	table := b.getTableRows(obInfo)
 	row := make([]any, 0, len(table.columnIndexes))
 	err := obInfo.fields.RangeLogical(func(fieldName seed.CodeName, fi *fieldInfo) error {
-		fieldValue := m[K(fieldName)]
+		fieldValue, found := m[K(fieldName)]
+		if !found || !fi.Nullable && isNilPointer(fieldValue) {
+			if defaultValue, ok := fi.DefaultValue(b.now); ok {
+				fieldValue = defaultValue
+			}
+		}
 		valueColumns := fi.cols
 		if isNilPointer(fieldValue) {
 			if fi.Nullable {
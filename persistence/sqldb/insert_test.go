@@ -52,6 +52,29 @@ func testAddDomain(t *testing.T, ctx context.Context, db *sqldb.DB, domain seed.
 	return success
 }
 
+// newSqlite3Object opens an in-memory sqlite3 database and adds the domain "test" with one object
+// named name holding fields. The table of the object is test_<name>.
+func newSqlite3Object(t *testing.T, ctx context.Context, name seed.CodeName, fields ...*seed.Field) (*sql.DB, *sqldb.DB, *seed.Object) {
+	t.Helper()
+	rawDB, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, rawDB.Close())
+	})
+	db, err := sqldb.New(rawDB, sqldb.Sqlite)
+	require.NoError(t, err)
+	ob := &seed.Object{
+		Thing: seed.Thing{Name: name},
+		FieldGroup: seed.FieldGroup{
+			Fields: must.V(seed.NewFields(fields...)),
+		},
+	}
+	domain := must.V(seed.NewDomain(seed.Thing{Name: "test"}, ob))
+	require.NoError(t, domain.Validate())
+	require.NoError(t, db.AddDomain(ctx, domain))
+	return rawDB, db, ob
+}
+
 type successCounter map[string]int
 
 func testInserts(t *testing.T, ctx context.Context, db *sqldb.DB, counter successCounter) (success bool) {
@@ -93,3 +116,19 @@ func countedStringSet[T any](vs []T) map[string]int {
 	}
 	return out
 }
+
+func TestInsertTypedValuesSqlite3(t *testing.T) {
+	ctx := context.Background()
+	text := testdomain.TextLineField()
+	rawDB, db, ob := newSqlite3Object(t, ctx, "texts", text)
+
+	value := seed.NewObjectValue()
+	require.NoError(t, seed.SetField(value, text, "typed", nil))
+	type textStruct struct {
+		Text string `seed:"text_10"`
+	}
+	require.NoError(t, db.InsertObjects(ctx, map[seed.CodeName]any{ob.Name: []any{value, *value, textStruct{Text: "typed"}}}))
+	var count int
+	require.NoError(t, rawDB.QueryRowContext(ctx, "SELECT count(*) FROM test_texts WHERE text_10 = 'typed'").Scan(&count))
+	assert.Equal(t, 3, count)
+}
@@ -186,12 +186,18 @@ type ColumnType string
 type ColumnConstraint struct {
 	PrimaryKey bool
 	NotNull    bool
+	Default    *Expression
 }
 
 func (c ColumnConstraint) writeTo(w *writeWarpper) {
 	if c.PrimaryKey {
 		w.write([]byte("PRIMARY KEY "))
 	}
+	if c.Default != nil {
+		w.write([]byte("DEFAULT "))
+		w.writeArg(*c.Default)
+		w.write([]byte(" "))
+	}
 	if c.NotNull {
 		w.write([]byte("NOT NULL"))
 	}
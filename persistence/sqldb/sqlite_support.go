@@ -1,6 +1,8 @@
 package sqldb
 
 import (
+	"strings"
+
 	"github.com/xiegeo/seed"
 )
 
@@ -16,9 +18,27 @@ const (
 func Sqlite(op *DBOption) error {
 	op.ColumnFeatures = SqliteColumnFeatures()
 	op.TableOption = sqliteTableDefinition
+	op.TimeNow = sqliteTimeNow
 	return nil
 }
 
+// sqliteTimeNow formats the current time by strftime, which is precise to milliseconds.
+// Layouts finer than milliseconds are padded by zeros.
+func sqliteTimeNow(layout string) (Expression, bool) {
+	const msLayout = "2006-01-02T15:04:05.000"
+	switch {
+	case layout == "2006-01-02":
+		return ValueLiteral("(strftime('%Y-%m-%d', 'now'))"), true
+	case layout == "2006-01-02T15:04:05":
+		return ValueLiteral("(strftime('%Y-%m-%dT%H:%M:%S', 'now'))"), true
+	case layout == msLayout:
+		return ValueLiteral("(strftime('%Y-%m-%dT%H:%M:%f', 'now'))"), true
+	case strings.HasPrefix(layout, msLayout) && strings.Trim(layout[len(msLayout):], "0") == "":
+		return ValueLiteral("(strftime('%Y-%m-%dT%H:%M:%f', 'now') || '" + layout[len(msLayout):] + "')"), true
+	}
+	return Expression{}, false
+}
+
 func SqliteColumnFeatures() (features ColumnFeatures) {
 	features.MustAppend("TEXT", false, &seed.Field{
 		FieldType: seed.String,
@@ -1,13 +1,22 @@
 package seed
 
+// Query selects values of objects.
+//
+// Query is encoded to JSON with the default encoding of its fields, and empty fields are omitted.
+// Conditions are encoded with typed literals, see Condition.MarshalJSON.
 type Query struct {
 	ObjectName ObjectNamePath
-	Fields     NameTree
-	Condition  Condition
-	Count      bool
-	Order      []PartialOrder
-	Offset     int64
-	Limit      int64 // future: use order and last row data for offset condition
+	Fields     NameTree       `json:",omitempty"`
+	Condition  Condition      // null if not set
+	Count      bool           `json:",omitempty"`
+	Order      []PartialOrder `json:",omitempty"`
+	Offset     int64          `json:",omitempty"`
+	Limit      int64          `json:",omitempty"` // see Cursor for paging without offset
+
+	// Grouping, see Aggregate. When set, selected Fields and Order must be in GroupBy or name aggregates.
+	GroupBy    []Path      `json:",omitempty"`
+	Aggregates []Aggregate `json:",omitempty"`
+	Having     Condition   // applied to groups after Condition is applied to rows, null if not set
 }
 
 type ObjectNamePath struct {
@@ -19,5 +28,9 @@ type NameTree map[CodeName]NameTree
 
 type PartialOrder struct {
 	FieldPath []CodeName
-	Inverse   bool
+	Inverse   bool `json:",omitempty"`
+}
+
+func (q Query) isGrouped() bool {
+	return len(q.GroupBy)+len(q.Aggregates) > 0
 }
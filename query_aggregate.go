@@ -0,0 +1,149 @@
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// Aggregate is a value computed from a group of rows, such as a count or a sum.
+//
+// The result is named by Name, which can be used by Having and Order of the query.
+// Null values are skipped, and the results of functions other than AggregateCount are null
+// if no values are found.
+type Aggregate struct {
+	Name      CodeName
+	Function  AggregateFunction
+	FieldPath Path `json:",omitempty"` // counts rows if empty, required by other functions
+}
+
+// AggregateFunction is the function of an Aggregate.
+type AggregateFunction uint8
+
+const (
+	AggregateCount AggregateFunction = iota // number of rows, or non-null values of a field
+	AggregateSum                            // sum of Integer or Real fields
+	AggregateMin                            // minimum of orderable fields
+	AggregateMax                            // maximum of orderable fields
+	AggregateAvg                            // average of Integer, Real or TimeStamp fields
+
+	AggregateFunctionMax = AggregateAvg
+)
+
+var _aggregateFunctionStringer = []string{"Count", "Sum", "Min", "Max", "Avg"}
+
+func (f AggregateFunction) String() string {
+	if f > AggregateFunctionMax {
+		return fmt.Sprintf("AggregateFunction(%d) out of range[0,%d]", f, AggregateFunctionMax)
+	}
+	return _aggregateFunctionStringer[f]
+}
+
+func (f AggregateFunction) MarshalText() ([]byte, error) {
+	return marshalEnum("AggregateFunction", _aggregateFunctionStringer, f)
+}
+
+func (f *AggregateFunction) UnmarshalText(text []byte) error {
+	return unmarshalEnum("AggregateFunction", _aggregateFunctionStringer, f, text)
+}
+
+// AppliesTo returns true if f can aggregate values of field type t.
+func (f AggregateFunction) AppliesTo(t FieldType) bool {
+	switch f {
+	case AggregateCount:
+		return true
+	case AggregateSum:
+		return t == Integer || t == Real
+	case AggregateMin, AggregateMax:
+		return t.IsOrderable()
+	case AggregateAvg:
+		return t == Integer || t == Real || t == TimeStamp
+	}
+	return false
+}
+
+// maxGroupRows is the most rows a group can have, used to bound sums and counts.
+var maxGroupRows = big.NewInt(math.MaxInt64)
+
+// ResultField returns the field describing the results of a, computed from values of source.
+// Source is nil when counting rows.
+//
+// Results keep the unit of source. Sums of integers are bounded by the sum of the most rows a
+// group can have, so they often need *big.Int. Averages of integers are Float64 reals.
+func (a Aggregate) ResultField(source *Field) (*Field, error) {
+	out := &Field{Thing: Thing{Name: a.Name}, Nullable: a.Function != AggregateCount}
+	if a.Function == AggregateCount {
+		out.FieldType = Integer
+		out.FieldTypeSetting = IntegerSetting{Min: big.NewInt(0), Max: new(big.Int).Set(maxGroupRows)}
+		return out, nil
+	}
+	if source == nil {
+		return nil, seederrors.NewFieldNotFoundError(a.Name)
+	}
+	if source.IsI18n || !a.Function.AppliesTo(source.FieldType) {
+		return nil, seederrors.NewFieldNotSupportedError(a.Function.String(), source.Name)
+	}
+	out.FieldType, out.FieldTypeSetting = source.FieldType, source.FieldTypeSetting
+	switch s := source.FieldTypeSetting.(type) {
+	case IntegerSetting:
+		switch a.Function { //nolint:exhaustive // Min and Max keep the setting of source
+		case AggregateSum:
+			out.FieldTypeSetting = IntegerSetting{Min: sumBound(s.Min, -1), Max: sumBound(s.Max, 1), Unit: s.Unit}
+		case AggregateAvg:
+			out.FieldType = Real
+			out.FieldTypeSetting = RealSetting{Standard: Float64, Unit: s.Unit}
+		}
+	case RealSetting:
+		if a.Function == AggregateSum {
+			out.FieldTypeSetting = RealSetting{Standard: sumStandard(s.Standard), Base: s.Base, Unit: s.Unit}
+		}
+	}
+	return out, nil
+}
+
+// sumBound returns the bound of sums of values bounded by b, growing in the direction of sign.
+func sumBound(b *big.Int, sign int) *big.Int {
+	if b == nil || b.Sign() != sign {
+		return b
+	}
+	return new(big.Int).Mul(b, maxGroupRows)
+}
+
+// sumStandard returns the standard of sums of values in s, widening 32 bit standards to 64 bits.
+func sumStandard(s RealStandard) RealStandard {
+	switch s { //nolint:exhaustive // other standards are kept
+	case Float32:
+		return Float64
+	case Decimal32:
+		return Decimal64
+	}
+	return s
+}
+
+// AggregateFields returns the result fields of q.Aggregates, see Aggregate.ResultField.
+func (q Query) AggregateFields(d DomainGetter) ([]*Field, error) {
+	ob, ok := d.GetObjects().Get(q.ObjectName.Object)
+	if !ok {
+		return nil, seederrors.NewObjectNotFoundError(q.ObjectName.Object)
+	}
+	v := queryValidator{domain: d}
+	out := make([]*Field, len(q.Aggregates))
+	for i, a := range q.Aggregates {
+		var source *Field
+		if len(a.FieldPath) > 0 {
+			f, ok := v.resolve(ob, a.FieldPath, "Aggregates", string(a.Name))
+			if !ok {
+				return nil, v.errs
+			}
+			source = f.Field
+		}
+		var err error
+		out[i], err = a.ResultField(source)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
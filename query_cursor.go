@@ -0,0 +1,187 @@
+package seed
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// Cursor marks the position after a row in the results of a query, for keyset (seek) pagination.
+//
+// Unlike Offset, a cursor gives stable pages when rows are inserted or deleted between queries,
+// and lets the next page be found by an index instead of skipping rows. A cursor is opaque to
+// clients, and is only valid for queries of the same object and order.
+type Cursor string
+
+type cursorJSON struct {
+	Order  []PartialOrder
+	Values []literalJSON
+}
+
+// KeysetOrder returns q.Order followed by the fields of the first identity of the queried object
+// that are not already ordered, so that rows are totally ordered. Ranges in an identity do not
+// overlap, so they are ordered by their starts. Grouped queries are ordered by GroupBy instead.
+func (q Query) KeysetOrder(d DomainGetter) ([]PartialOrder, error) {
+	ob, ok := d.GetObjects().Get(q.ObjectName.Object)
+	if !ok {
+		return nil, seederrors.NewObjectNotFoundError(q.ObjectName.Object)
+	}
+	var keys []Path
+	if q.isGrouped() {
+		keys = q.GroupBy
+	} else {
+		id, ok := FindIdentity(ob, "")
+		if !ok {
+			return nil, seederrors.NewSystemError("object %s has no identity to order rows for keyset pagination", q.ObjectName.Object)
+		}
+		for _, name := range id.Fields {
+			keys = append(keys, Path{name})
+		}
+		for _, r := range id.Ranges {
+			keys = append(keys, Path{r.Start})
+		}
+	}
+	order := append([]PartialOrder{}, q.Order...)
+	for _, key := range keys {
+		if !hasOrder(order, key) {
+			order = append(order, PartialOrder{FieldPath: key})
+		}
+	}
+	return order, nil
+}
+
+func hasOrder(order []PartialOrder, fieldPath []CodeName) bool {
+	for _, o := range order {
+		if pathEqual(o.FieldPath, fieldPath) {
+			return true
+		}
+	}
+	return false
+}
+
+func pathEqual(a, b []CodeName) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Cursor returns the cursor after lastRow, the last row of a page returned by q ordered by KeysetOrder.
+// Values are found by resolver, or ResolvePath if resolver is nil. Ordered values must not be null.
+func (q Query) Cursor(d DomainGetter, lastRow any, resolver PathResolver) (Cursor, error) {
+	order, err := q.KeysetOrder(d)
+	if err != nil {
+		return "", err
+	}
+	if resolver == nil {
+		resolver = ResolvePath
+	}
+	c := cursorJSON{Order: order, Values: make([]literalJSON, len(order))}
+	for i, o := range order {
+		v, err := resolver(lastRow, o.FieldPath)
+		if err != nil {
+			return "", err
+		}
+		v = normalizeOperand(v)
+		if v == nil {
+			return "", seederrors.NewValueRequiredError(strings.Join(pathStrings(o.FieldPath), "."))
+		}
+		c.Values[i], err = marshalLiteral(v)
+		if err != nil {
+			return "", err
+		}
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return Cursor(base64.RawURLEncoding.EncodeToString(data)), nil
+}
+
+// After returns the query for the page after cursor. It is q ordered by KeysetOrder, without Offset,
+// and with a condition that selects rows ordered after the row of the cursor:
+//
+//	Or(Gt(a, a0), And(Eq(a, a0), Gt(b, b0)), ...)
+//
+// where inverse orders use Lt instead of Gt. For grouped queries, the condition is added to Having.
+// Each page should be queried from the same q, and not from a query returned by After.
+func (q Query) After(d DomainGetter, cursor Cursor) (Query, error) {
+	order, err := q.KeysetOrder(d)
+	if err != nil {
+		return Query{}, err
+	}
+	invalid := seederrors.NewQueryError(seederrors.QueryCursor, cursor, "Cursor")
+	data, err := base64.RawURLEncoding.DecodeString(string(cursor))
+	if err != nil {
+		return Query{}, invalid
+	}
+	var c cursorJSON
+	err = json.Unmarshal(data, &c)
+	if err != nil || len(c.Values) != len(order) || !orderEqual(c.Order, order) {
+		return Query{}, invalid
+	}
+	values := make([]any, len(order))
+	for i, lit := range c.Values {
+		values[i], err = unmarshalLiteral(lit)
+		if err != nil {
+			return Query{}, invalid
+		}
+	}
+	seek := Condition{Op: Or}
+	for i := range order {
+		step := Condition{Op: And}
+		for j := 0; j <= i; j++ {
+			op := Eq
+			if j == i {
+				op = Gt
+				if order[j].Inverse {
+					op = Lt
+				}
+			}
+			step.Children = append(step.Children, Condition{Op: op, FieldPaths: []Path{order[j].FieldPath}, Literal: values[j]})
+		}
+		if len(step.Children) == 1 {
+			step = step.Children[0]
+		}
+		seek.Children = append(seek.Children, step)
+	}
+	if len(seek.Children) == 1 {
+		seek = seek.Children[0]
+	}
+	out := q
+	out.Order = order
+	out.Offset = 0
+	if q.isGrouped() {
+		out.Having = andCondition(q.Having, seek)
+	} else {
+		out.Condition = andCondition(q.Condition, seek)
+	}
+	return out, nil
+}
+
+// andCondition returns the condition of both a and b, where a can be the zero condition.
+func andCondition(a, b Condition) Condition {
+	if a.isZero() {
+		return b
+	}
+	return Condition{Op: And, Children: []Condition{a, b}}
+}
+
+func orderEqual(a, b []PartialOrder) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Inverse != b[i].Inverse || !pathEqual(a[i].FieldPath, b[i].FieldPath) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,295 @@
+package seed_test
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
+
+	. "github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/demo/testdomain"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+func queryTestDomain() *Domain {
+	event := &Object{
+		Thing: Thing{Name: "event"},
+		FieldGroup: FieldGroup{
+			Fields: must.V(NewFields(
+				testdomain.TextLineField(),
+				testdomain.Bool(),
+				testdomain.DateTimeSec(),
+				testdomain.JSInteger(),
+				testdomain.Decimal64(),
+				testdomain.Enumeration(),
+				testdomain.ListOf(testdomain.Bool(), ListSetting{MaxLength: 3}),
+				&Field{
+					Thing:            Thing{Name: "ref"},
+					FieldType:        Reference,
+					FieldTypeSetting: ReferenceSetting{Object: "level_0"},
+				},
+				&Field{
+					Thing:            Thing{Name: "period"},
+					FieldType:        Combination,
+					FieldTypeSetting: CombinationSetting{Fields: must.V(NewFields(testdomain.DateTimeSec()))},
+				},
+			)),
+		},
+	}
+	return must.V(NewDomain(Thing{Name: "query_test"}, testdomain.ObjLevel0(), event))
+}
+
+func TestQueryValidate(t *testing.T) {
+	d := queryTestDomain()
+	require.NoError(t, d.Validate())
+	q := Query{
+		ObjectName: ObjectNamePath{Domain: "query_test", Object: "event"},
+		Fields: NameTree{
+			"text_10": nil,
+			"ref":     NameTree{"bool": nil},
+			"period":  NameTree{"datetime_sec_9999": nil},
+		},
+		Condition: must.V(ParseCondition(`And(
+			bool,
+			Lt(datetime_sec_9999, t"2023-01-01T00:00:00Z"),
+			Gte(ref.integer_js, integer_js, 3),
+			In(status, ["draft", "published"]),
+			Eq(decimal_64, d"1.5"),
+			In(list_bool, true),
+			AndIsNull(ref))`)),
+		Order:  []PartialOrder{{FieldPath: []CodeName{"ref", "text_10"}, Inverse: true}},
+		Offset: 10,
+		Limit:  10,
+	}
+	require.NoError(t, q.Validate(d))
+
+	q.Condition.Children[2].FieldPaths[1] = Path{"period", "datetime_sec_9999", "no_such_thing_yet"}
+	q.ObjectName.Domain = "other"
+	q.Fields["ref"]["missing"] = nil
+	q.Fields["text_10"] = NameTree{"a": nil}
+	q.Order = append(q.Order, PartialOrder{FieldPath: []CodeName{"list_bool"}}, PartialOrder{})
+	q.Condition.Children = append(q.Condition.Children,
+		must.V(ParseCondition(`Lt(status, period, list_bool)`)),
+		must.V(ParseCondition(`Eq(integer_js, "1")`)),
+		must.V(ParseCondition(`In(status, ["draft", 1])`)),
+		must.V(ParseCondition(`Or(text_10, 1)`)),
+		must.V(ParseCondition(`Eq(decimal_64, "1.5")`)),
+		Condition{Op: Eq, Children: []Condition{{Op: PushUp, FieldPaths: []Path{{"bool"}}, Literal: int64(1)}}},
+		must.V(ParseCondition(`Eq(text_10, ["a", "b"])`)),
+		must.V(ParseCondition(`Lt(integer_js, [1, 2])`)),
+		Condition{Op: OpMax + 1},
+	)
+	q.Offset = -1
+	err := q.Validate(d)
+	var errs seederrors.QueryErrors
+	require.ErrorAs(t, err, &errs)
+	got := make([]string, len(errs))
+	for i, e := range errs {
+		got[i] = strings.Join(e.Path, ".") + ": " + string(e.Rule)
+	}
+	assert.Equal(t, []string{
+		"ObjectName.Domain: " + string(seederrors.QueryDomain),
+		"Fields.ref.missing: " + string(seederrors.QueryFieldNotFound),
+		"Fields.text_10: " + string(seederrors.QueryPathNotWalkable),
+		"Order.1.list_bool: " + string(seederrors.QueryNotOrderable),
+		"Order.2: " + string(seederrors.QueryFieldNotFound),
+		"Condition.2.period.datetime_sec_9999: " + string(seederrors.QueryPathNotWalkable),
+		"Condition.7.status: " + string(seederrors.QueryNotOrderable),
+		"Condition.7.period: " + string(seederrors.QueryNotOrderable),
+		"Condition.7.list_bool: " + string(seederrors.QueryNotOrderable),
+		"Condition.8.Literal: " + string(seederrors.QueryLiteralType),
+		"Condition.9.Literal: " + string(seederrors.QueryLiteralType),
+		"Condition.10.text_10: " + string(seederrors.QueryOperandType),
+		"Condition.10.Literal: " + string(seederrors.QueryLiteralType),
+		"Condition.11.Literal: " + string(seederrors.QueryLiteralType),
+		"Condition.12.0.Literal: " + string(seederrors.QueryLiteralType),
+		"Condition.13.Literal: " + string(seederrors.QueryLiteralType),
+		"Condition.14.Literal: " + string(seederrors.QueryLiteralType),
+		"Condition.15: " + string(seederrors.QueryOperator),
+		"Offset: " + string(seederrors.QueryNegative),
+	}, got)
+
+	q = Query{ObjectName: ObjectNamePath{Domain: "query_test", Object: "missing"}}
+	assert.ErrorContains(t, q.Validate(d), string(seederrors.QueryObjectNotFound))
+	q = Query{
+		ObjectName: ObjectNamePath{Domain: "query_test", Object: "event"},
+		Condition:  Condition{Op: PushUp, FieldPaths: []Path{{"bool"}}},
+	}
+	assert.ErrorContains(t, q.Validate(d), string(seederrors.QueryOperator), "PushUp can not be the root")
+	q.Condition = must.V(ParseCondition(`And(Gte(decimal_64, 1, 0.5), NotIn(integer_js, [1, 2]))`))
+	assert.NoError(t, q.Validate(d), "integers and floats are compared with decimals")
+}
+
+func TestQueryCursor(t *testing.T) {
+	d := queryTestDomain()
+	rows := []map[CodeName]any{}
+	for i, text := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		rows = append(rows, map[CodeName]any{
+			"text_10":    text,
+			"bool":       i%3 == 0,
+			"integer_js": int64(i % 2),
+		})
+	}
+	q := Query{
+		ObjectName: ObjectNamePath{Domain: "query_test", Object: "level_0"},
+		Condition:  must.V(ParseCondition(`Neq(text_10, "c")`)),
+		Order:      []PartialOrder{{FieldPath: []CodeName{"bool"}, Inverse: true}, {FieldPath: []CodeName{"integer_js"}}},
+		Offset:     100,
+		Limit:      2,
+	}
+	order, err := q.KeysetOrder(d)
+	require.NoError(t, err)
+	assert.Equal(t, append(q.Order, PartialOrder{FieldPath: []CodeName{"text_10"}}), order)
+
+	// page runs a query over rows, as a database would.
+	page := func(q Query) []map[CodeName]any {
+		var out []map[CodeName]any
+		for _, row := range rows {
+			if must.V(q.Condition.Evaluate(row, nil)) == True {
+				out = append(out, row)
+			}
+		}
+		sort.SliceStable(out, func(i, j int) bool {
+			for _, o := range q.Order {
+				cmp := must.V(CompareValues(out[i][o.FieldPath[0]], out[j][o.FieldPath[0]]))
+				if o.Inverse {
+					cmp = -cmp
+				}
+				if cmp != 0 {
+					return cmp < 0
+				}
+			}
+			return false
+		})
+		if int64(len(out)) > q.Limit {
+			out = out[:q.Limit]
+		}
+		return out
+	}
+	var got []any
+	next := q
+	next.Order, next.Offset = order, 0
+	for {
+		rs := page(next)
+		if len(rs) == 0 {
+			break
+		}
+		for _, r := range rs {
+			got = append(got, r["text_10"])
+		}
+		cursor, err := q.Cursor(d, rs[len(rs)-1], nil)
+		require.NoError(t, err)
+		next, err = q.After(d, cursor)
+		require.NoError(t, err)
+		assert.Zero(t, next.Offset)
+	}
+	assert.Equal(t, []any{"a", "g", "d", "e", "b", "f"}, got)
+
+	_, err = q.After(d, "bad cursor")
+	assert.ErrorContains(t, err, string(seederrors.QueryCursor))
+	_, err = q.Cursor(d, map[CodeName]any{"text_10": "a"}, nil)
+	assert.ErrorAs(t, err, &seederrors.ValueRequiredError{}, "ordered values can not be null")
+	cursor := must.V(q.Cursor(d, rows[0], nil))
+	q.Order = nil
+	_, err = q.After(d, cursor)
+	assert.ErrorContains(t, err, string(seederrors.QueryCursor), "order changed")
+	q.ObjectName.Object = "event"
+	_, err = q.KeysetOrder(d)
+	assert.Error(t, err, "event has no identity")
+}
+
+func TestQueryAggregate(t *testing.T) {
+	d := queryTestDomain()
+	q := Query{
+		ObjectName: ObjectNamePath{Domain: "query_test", Object: "event"},
+		Fields:     NameTree{"status": nil},
+		Condition:  must.V(ParseCondition(`Eq(bool, true)`)),
+		GroupBy:    []Path{{"status"}},
+		Aggregates: []Aggregate{
+			{Name: "n", Function: AggregateCount},
+			{Name: "total", Function: AggregateSum, FieldPath: Path{"integer_js"}},
+			{Name: "mean", Function: AggregateAvg, FieldPath: Path{"integer_js"}},
+			{Name: "latest", Function: AggregateMax, FieldPath: Path{"period", "datetime_sec_9999"}},
+		},
+		Having: must.V(ParseCondition(`And(Gt(n, 1), Lt(mean, 10.0))`)),
+		Order:  []PartialOrder{{FieldPath: []CodeName{"total"}, Inverse: true}},
+	}
+	require.NoError(t, q.Validate(d))
+
+	fields, err := q.AggregateFields(d)
+	require.NoError(t, err)
+	require.Len(t, fields, 4)
+	assert.Equal(t, Integer, fields[0].FieldType)
+	assert.False(t, fields[0].Nullable, "counts are never null")
+	sum := fields[1].FieldTypeSetting.(IntegerSetting)
+	assert.False(t, sum.Max.IsInt64(), "sums of integers need *big.Int")
+	assert.Equal(t, 0, sum.Max.Cmp(new(big.Int).Mul(testdomain.JSInteger().FieldTypeSetting.(IntegerSetting).Max, big.NewInt(math.MaxInt64))))
+	assert.True(t, fields[1].Nullable)
+	assert.Equal(t, Real, fields[2].FieldType)
+	assert.Equal(t, Float64, fields[2].FieldTypeSetting.(RealSetting).Standard)
+	assert.Equal(t, testdomain.DateTimeSec().FieldTypeSetting, fields[3].FieldTypeSetting)
+
+	fields[0].FieldTypeSetting.(IntegerSetting).Max.SetInt64(1)
+	again, err := q.AggregateFields(d)
+	require.NoError(t, err)
+	assert.Equal(t, int64(math.MaxInt64), again[0].FieldTypeSetting.(IntegerSetting).Max.Int64(), "bounds are not shared")
+	for standard, want := range map[RealStandard]RealStandard{Float32: Float64, Decimal32: Decimal64, Decimal64: Decimal64} {
+		source := testdomain.Float64()
+		source.FieldTypeSetting = RealSetting{Standard: standard}
+		f, err := Aggregate{Name: "a", Function: AggregateSum}.ResultField(source)
+		require.NoError(t, err)
+		assert.Equal(t, want, f.FieldTypeSetting.(RealSetting).Standard)
+	}
+
+	_, err = Aggregate{Name: "a", Function: AggregateSum}.ResultField(testdomain.DateTimeSec())
+	assert.ErrorAs(t, err, &seederrors.FieldNotSupportedError{})
+
+	order, err := q.KeysetOrder(d)
+	require.NoError(t, err)
+	assert.Equal(t, append(q.Order, PartialOrder{FieldPath: Path{"status"}}), order, "groups are ordered by GroupBy")
+
+	data, err := json.Marshal(q)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Function":"Sum"`)
+	var decoded Query
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, q, decoded)
+
+	q.Fields["text_10"] = nil
+	q.Order = append(q.Order, PartialOrder{FieldPath: []CodeName{"datetime_sec_9999"}})
+	q.GroupBy = append(q.GroupBy, Path{"list_bool"})
+	q.Aggregates = append(q.Aggregates,
+		Aggregate{Name: "bool", Function: AggregateCount},
+		Aggregate{Name: "n", Function: AggregateSum, FieldPath: Path{"status"}},
+		Aggregate{Name: "avg", Function: AggregateAvg},
+	)
+	q.Having.Children = append(q.Having.Children, must.V(ParseCondition(`Gt(n, "1")`)))
+	var errs seederrors.QueryErrors
+	require.ErrorAs(t, q.Validate(d), &errs)
+	got := make([]string, len(errs))
+	for i, e := range errs {
+		got[i] = strings.Join(e.Path, ".") + ": " + string(e.Rule)
+	}
+	assert.Equal(t, []string{
+		"GroupBy.1.list_bool: " + string(seederrors.QueryGroupBy),
+		"Aggregates.4: " + string(seederrors.QueryAggregateName),
+		"Aggregates.5: " + string(seederrors.QueryAggregateName),
+		"Aggregates.5.status: " + string(seederrors.QueryAggregate),
+		"Aggregates.6: " + string(seederrors.QueryFieldNotFound),
+		"Fields.text_10: " + string(seederrors.QueryNotGrouped),
+		"Order.1.datetime_sec_9999: " + string(seederrors.QueryNotGrouped),
+		"Having.2.Literal: " + string(seederrors.QueryLiteralType),
+	}, got)
+
+	q = Query{
+		ObjectName: ObjectNamePath{Domain: "query_test", Object: "event"},
+		Having:     must.V(ParseCondition(`Eq(bool, true)`)),
+	}
+	assert.ErrorContains(t, q.Validate(d), string(seederrors.QueryNotGrouped), "Having requires grouping")
+}
@@ -0,0 +1,109 @@
+package seed
+
+import (
+	"reflect"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// Quantity is a number in a unit. As a literal of a condition, it is compared with fields in a
+// convertible unit, after conversion by Query.ConvertQuantities.
+type Quantity struct {
+	Value any // int64, *big.Int, float64 or decimal.Decimal
+	Unit  *Unit
+}
+
+// ConvertQuantities returns q with Quantity literals in Condition and Having converted to the units of
+// the fields they are compared with, as plain values of the type of Quantity.Value.
+// Quantities must be compared with a field path in the same condition, see ConvertUnit for the rules
+// of conversions.
+func (q Query) ConvertQuantities(d DomainGetter) (Query, error) {
+	ob, ok := d.GetObjects().Get(q.ObjectName.Object)
+	if !ok {
+		return Query{}, seederrors.NewObjectNotFoundError(q.ObjectName.Object)
+	}
+	v := queryValidator{domain: d} // only used to find fields, errors are reported by Validate
+	var err error
+	if !q.Condition.isZero() {
+		q.Condition, err = convertQuantities(v.fieldsOf(ob), q.Condition, nil)
+		if err != nil {
+			return Query{}, err
+		}
+	}
+	if !q.Having.isZero() && q.isGrouped() {
+		q.Having, err = convertQuantities(v.grouping(ob, q), q.Having, nil)
+		if err != nil {
+			return Query{}, err
+		}
+	}
+	return q, nil
+}
+
+// convertQuantities converts literals of c to the unit of the first field path of c, which is found
+// from c unless c is a PushUp condition of the parent condition that has target.
+func convertQuantities(resolve fieldResolver, c Condition, target *queryField) (Condition, error) {
+	if c.Op != PushUp {
+		target = nil
+		for _, operand := range collectOperands(c, nil, nil) {
+			if operand.fieldPath == nil {
+				continue
+			}
+			if f, ok := resolve(operand.fieldPath); ok {
+				target = &f
+				break
+			}
+		}
+	}
+	out := c
+	if len(c.Children) > 0 {
+		out.Children = make([]Condition, len(c.Children))
+		for i, child := range c.Children {
+			var err error
+			out.Children[i], err = convertQuantities(resolve, child, target)
+			if err != nil {
+				return Condition{}, err
+			}
+		}
+	}
+	if c.Literal != nil {
+		var err error
+		out.Literal, err = convertLiteral(c.Literal, target)
+		if err != nil {
+			return Condition{}, err
+		}
+	}
+	return out, nil
+}
+
+func convertLiteral(v any, target *queryField) (any, error) {
+	switch vt := v.(type) {
+	case Quantity:
+		if target == nil {
+			return nil, seederrors.NewValueError(seederrors.ValueUnit, vt.Value, string(unitName(vt.Unit)))
+		}
+		return ConvertUnit(vt.Value, vt.Unit, settingUnit(target.Setting))
+	case *Quantity:
+		return convertLiteral(*vt, target)
+	case []byte:
+		return v, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return v, nil
+	}
+	items := make([]any, rv.Len())
+	changed := false
+	for i := range items {
+		item := rv.Index(i).Interface()
+		var err error
+		items[i], err = convertLiteral(item, target)
+		if err != nil {
+			return nil, err
+		}
+		changed = changed || !reflect.DeepEqual(item, items[i])
+	}
+	if !changed {
+		return v, nil // keep lists without quantities as they are
+	}
+	return items, nil
+}
@@ -0,0 +1,406 @@
+package seed
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// Validate checks that q can be run against the domain d:
+//
+//   - ObjectName names d and one of its objects.
+//   - Fields and Order only name fields that exist, walking through references and combinations.
+//   - Condition uses valid operators, and field paths that exist.
+//   - Literals in Condition match the types of the fields they are compared with.
+//   - Directional operators and Order only apply to orderable fields, lists and combinations are not orderable.
+//   - Offset and Limit are not negative.
+//   - When grouped, GroupBy and Aggregates apply to fields that exist, and Fields, Order and Having
+//     only use grouped fields and aggregates. Aggregate names do not repeat or hide field names.
+//
+// All violations found are returned together as seederrors.QueryErrors, or nil if none found.
+func (q Query) Validate(d DomainGetter) error {
+	v := queryValidator{domain: d}
+	v.query(q)
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+type queryValidator struct {
+	domain DomainGetter
+	errs   seederrors.QueryErrors
+}
+
+func (v *queryValidator) add(rule seederrors.QueryRule, value any, path ...string) {
+	v.errs = append(v.errs, seederrors.NewQueryError(rule, value, path...))
+}
+
+func (v *queryValidator) query(q Query) {
+	if q.ObjectName.Domain != v.domain.GetName() {
+		v.add(seederrors.QueryDomain, q.ObjectName.Domain, "ObjectName", "Domain")
+	}
+	ob, ok := v.domain.GetObjects().Get(q.ObjectName.Object)
+	if !ok {
+		v.add(seederrors.QueryObjectNotFound, q.ObjectName.Object, "ObjectName", "Object")
+		return
+	}
+	rows := v.fieldsOf(ob)
+	selected := rows
+	if q.isGrouped() {
+		selected = v.grouping(ob, q)
+		for _, leaf := range nameTreeLeaves(q.Fields, nil) {
+			selected(leaf, "Fields")
+		}
+	} else {
+		v.nameTree(ob, q.Fields, "Fields")
+	}
+	for i, order := range q.Order {
+		path := []string{"Order", strconv.Itoa(i)}
+		f, ok := selected(order.FieldPath, path...)
+		if ok && !f.isOrderable() {
+			v.add(seederrors.QueryNotOrderable, f, withPath(path, pathStrings(order.FieldPath)...)...)
+		}
+	}
+	if !q.Condition.isZero() {
+		v.condition(rows, q.Condition, "Condition")
+	}
+	if !q.Having.isZero() {
+		if q.isGrouped() {
+			v.condition(selected, q.Having, "Having")
+		} else {
+			v.add(seederrors.QueryNotGrouped, "", "Having")
+		}
+	}
+	if q.Offset < 0 {
+		v.add(seederrors.QueryNegative, q.Offset, "Offset")
+	}
+	if q.Limit < 0 {
+		v.add(seederrors.QueryNegative, q.Limit, "Limit")
+	}
+}
+
+// queryField is the type of values found by walking a field path.
+// Lists and i18n fields are unwrapped to the type of their items.
+type queryField struct {
+	Field     *Field
+	FieldType FieldType
+	Setting   FieldTypeSetting
+	Multiple  bool // values are lists or i18n
+}
+
+func (f queryField) String() string {
+	if f.Multiple {
+		return "multiple " + f.FieldType.String()
+	}
+	return f.FieldType.String()
+}
+
+func (f queryField) isOrderable() bool {
+	return !f.Multiple && f.FieldType.IsOrderable()
+}
+
+func (v *queryValidator) getField(g FieldGroupGetter, cn CodeName, path []string) (queryField, bool) {
+	var f *Field
+	var ok bool
+	if fields := g.GetFields(); fields != nil {
+		f, ok = fields.Get(cn)
+	}
+	if !ok {
+		v.add(seederrors.QueryFieldNotFound, cn, path...)
+		return queryField{}, false
+	}
+	out := queryField{Field: f, FieldType: f.FieldType, Setting: f.FieldTypeSetting, Multiple: f.IsI18n}
+	if ls, ok := f.FieldTypeSetting.(ListSetting); ok {
+		out.FieldType, out.Setting, out.Multiple = ls.ItemType, ls.ItemTypeSetting, true
+	}
+	return out, true
+}
+
+// fieldGroup returns the fields nested in a reference or combination.
+func (v *queryValidator) fieldGroup(f queryField, path []string) (FieldGroupGetter, bool) {
+	switch s := f.Setting.(type) {
+	case CombinationSetting:
+		return &s, true
+	case ReferenceSetting:
+		ob, ok := v.domain.GetObjects().Get(s.Object)
+		if !ok {
+			v.add(seederrors.QueryObjectNotFound, s.Object, path...)
+		}
+		return ob, ok
+	}
+	v.add(seederrors.QueryPathNotWalkable, f, path...)
+	return nil, false
+}
+
+// resolve walks fieldPath from g, and reports the first name that can not be walked.
+func (v *queryValidator) resolve(g FieldGroupGetter, fieldPath []CodeName, path ...string) (queryField, bool) {
+	if len(fieldPath) == 0 {
+		v.add(seederrors.QueryFieldNotFound, "", path...)
+		return queryField{}, false
+	}
+	last := len(fieldPath) - 1
+	for i, cn := range fieldPath[:last] {
+		namePath := withPath(path, pathStrings(fieldPath[:i+1])...)
+		f, ok := v.getField(g, cn, namePath)
+		if !ok {
+			return queryField{}, false
+		}
+		g, ok = v.fieldGroup(f, namePath)
+		if !ok {
+			return queryField{}, false
+		}
+	}
+	return v.getField(g, fieldPath[last], withPath(path, pathStrings(fieldPath)...))
+}
+
+func (v *queryValidator) nameTree(g FieldGroupGetter, tree NameTree, path ...string) {
+	for _, cn := range sortedNames(tree) {
+		namePath := withPath(path, string(cn))
+		f, ok := v.getField(g, cn, namePath)
+		if !ok || len(tree[cn]) == 0 {
+			continue
+		}
+		if sub, ok := v.fieldGroup(f, namePath); ok {
+			v.nameTree(sub, tree[cn], namePath...)
+		}
+	}
+}
+
+// grouping checks GroupBy and Aggregates, and returns the resolver of fields in groups, which
+// are the fields in GroupBy and the results of Aggregates by name.
+func (v *queryValidator) grouping(ob ObjectGetter, q Query) fieldResolver {
+	groups := make(map[string]*queryField) // nil if not valid, so it is only reported once
+	for i, fp := range q.GroupBy {
+		path := []string{"GroupBy", strconv.Itoa(i)}
+		f, ok := v.resolve(ob, fp, path...)
+		switch {
+		case !ok:
+			groups[pathKey(fp)] = nil
+		case f.Multiple || f.FieldType == Combination:
+			v.add(seederrors.QueryGroupBy, f, withPath(path, pathStrings(fp)...)...)
+			groups[pathKey(fp)] = nil
+		default:
+			groups[pathKey(fp)] = &f
+		}
+	}
+	aggregates := make(map[CodeName]*queryField)
+	for i, a := range q.Aggregates {
+		path := []string{"Aggregates", strconv.Itoa(i)}
+		_, repeated := aggregates[a.Name]
+		if a.Name == "" || repeated || v.hasField(ob, a.Name) {
+			v.add(seederrors.QueryAggregateName, a.Name, path...)
+		}
+		if !repeated {
+			aggregates[a.Name] = nil
+		}
+		if len(a.FieldPath) == 0 && a.Function != AggregateCount {
+			v.add(seederrors.QueryFieldNotFound, "", path...)
+			continue
+		}
+		var source *Field
+		if len(a.FieldPath) > 0 {
+			f, ok := v.resolve(ob, a.FieldPath, path...)
+			if !ok {
+				continue
+			}
+			source = f.Field
+		}
+		result, err := a.ResultField(source)
+		if err != nil {
+			v.add(seederrors.QueryAggregate, a.Function, withPath(path, pathStrings(a.FieldPath)...)...)
+			continue
+		}
+		if !repeated {
+			aggregates[a.Name] = &queryField{Field: result, FieldType: result.FieldType, Setting: result.FieldTypeSetting}
+		}
+	}
+	return func(fieldPath []CodeName, path ...string) (queryField, bool) {
+		f, found := groups[pathKey(fieldPath)]
+		if len(fieldPath) == 1 {
+			if af, ok := aggregates[fieldPath[0]]; ok {
+				f, found = af, true
+			}
+		}
+		if !found {
+			v.add(seederrors.QueryNotGrouped, pathKey(fieldPath), withPath(path, pathStrings(fieldPath)...)...)
+			return queryField{}, false
+		}
+		if f == nil {
+			return queryField{}, false
+		}
+		return *f, true
+	}
+}
+
+func (v *queryValidator) hasField(g FieldGroupGetter, cn CodeName) bool {
+	fields := g.GetFields()
+	if fields == nil {
+		return false
+	}
+	_, ok := fields.Get(cn)
+	return ok
+}
+
+func pathKey(fieldPath []CodeName) string {
+	return strings.Join(pathStrings(fieldPath), ".")
+}
+
+func sortedNames(tree NameTree) []CodeName {
+	names := make([]CodeName, 0, len(tree))
+	for cn := range tree {
+		names = append(names, cn)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// nameTreeLeaves returns the paths to the leaves of tree, in sorted order.
+func nameTreeLeaves(tree NameTree, prefix []CodeName) [][]CodeName {
+	var out [][]CodeName
+	for _, cn := range sortedNames(tree) {
+		path := append(append([]CodeName{}, prefix...), cn)
+		if len(tree[cn]) == 0 {
+			out = append(out, path)
+			continue
+		}
+		out = append(out, nameTreeLeaves(tree[cn], path)...)
+	}
+	return out
+}
+
+// queryOperand is an operand of a condition, with PushUp children expanded.
+type queryOperand struct {
+	path      []string
+	child     *Condition
+	fieldPath Path
+	literal   any
+}
+
+func collectOperands(c Condition, path []string, out []queryOperand) []queryOperand {
+	for i := range c.Children {
+		childPath := withPath(path, strconv.Itoa(i))
+		if c.Children[i].Op == PushUp {
+			out = collectOperands(c.Children[i], childPath, out)
+			continue
+		}
+		out = append(out, queryOperand{path: childPath, child: &c.Children[i]})
+	}
+	for _, fp := range c.FieldPaths {
+		out = append(out, queryOperand{path: path, fieldPath: fp})
+	}
+	if c.Literal != nil {
+		out = append(out, queryOperand{path: withPath(path, "Literal"), literal: c.Literal})
+	}
+	return out
+}
+
+// fieldResolver resolves field paths used by conditions, and reports errors at path.
+type fieldResolver func(fieldPath []CodeName, path ...string) (queryField, bool)
+
+func (v *queryValidator) fieldsOf(g FieldGroupGetter) fieldResolver {
+	return func(fieldPath []CodeName, path ...string) (queryField, bool) {
+		return v.resolve(g, fieldPath, path...)
+	}
+}
+
+func (v *queryValidator) condition(resolve fieldResolver, c Condition, path ...string) {
+	if c.Op == PushUp || c.Op > OpMax {
+		v.add(seederrors.QueryOperator, c.Op, path...)
+		return
+	}
+	isLogical := c.Op >= And && c.Op <= Nor
+	var compared []queryField
+	var literals []queryOperand
+	for _, operand := range collectOperands(c, path, nil) {
+		switch {
+		case operand.child != nil:
+			v.condition(resolve, *operand.child, operand.path...)
+			compared = append(compared, queryField{FieldType: Boolean})
+		case operand.fieldPath != nil:
+			f, ok := resolve(operand.fieldPath, operand.path...)
+			if !ok {
+				continue
+			}
+			fieldPath := withPath(operand.path, pathStrings(operand.fieldPath)...)
+			switch {
+			case isLogical && (f.FieldType != Boolean || f.Multiple):
+				v.add(seederrors.QueryOperandType, f, fieldPath...)
+			case c.Op.IsDirectional() && !f.isOrderable():
+				v.add(seederrors.QueryNotOrderable, f, fieldPath...)
+			default:
+				compared = append(compared, f)
+			}
+		default:
+			literals = append(literals, operand)
+		}
+	}
+	if isLogical {
+		compared = []queryField{{FieldType: Boolean}}
+	}
+	if c.Op >= AndIsNull && c.Op <= OrNotNull {
+		return // literals are never null
+	}
+	for _, lit := range literals {
+		for _, f := range compared {
+			if !literalMatches(f, lit.literal, c.Op == In || c.Op == NotIn) {
+				v.add(seederrors.QueryLiteralType, lit.literal, lit.path...)
+				break
+			}
+		}
+	}
+}
+
+// literalMatches returns true if v, or each item of v if v is a list and lists are allowed,
+// can be compared with values of f. Integers and floats can be compared with reals if they
+// convert exactly, see CompareValues.
+// Quantities must have units that convert to the unit of f.
+func literalMatches(f queryField, v any, allowList bool) bool {
+	v = derefValue(v)
+	if q, ok := v.(Quantity); ok {
+		_, _, err := q.Unit.Conversion(settingUnit(f.Setting))
+		return err == nil && literalMatches(f, q.Value, allowList)
+	}
+	if _, ok := v.([]byte); !ok {
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice {
+			if !allowList {
+				return false
+			}
+			for i := 0; i < rv.Len(); i++ {
+				if !literalMatches(f, rv.Index(i).Interface(), false) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	var ok bool
+	switch f.FieldType { //nolint:exhaustive // other field types can not be compared with literals
+	case String, Enumeration:
+		switch v.(type) {
+		case string, CodeName:
+			ok = true
+		}
+	case Binary:
+		_, ok = v.([]byte)
+	case Boolean:
+		_, ok = v.(bool)
+	case TimeStamp:
+		_, ok = v.(time.Time)
+	case Integer:
+		_, ok = toBigInt(v)
+	case Real:
+		like := any(float64(0))
+		if s, isReal := f.Setting.(RealSetting); isReal && s.Standard.IsDecimal() {
+			like = decimal.Zero
+		}
+		_, ok = toReal(v, like)
+	}
+	return ok
+}
@@ -0,0 +1,58 @@
+package seederrors
+
+type DefinitionRule string
+
+const (
+	DefinitionFieldType       DefinitionRule = `field type is not valid`
+	DefinitionSettingType     DefinitionRule = `field type setting does not match field type`
+	DefinitionSetting         DefinitionRule = `field type setting is not valid`
+	DefinitionFieldNotFound   DefinitionRule = `field is not defined`
+	DefinitionIdentityEmpty   DefinitionRule = `an identity must have fields listed`
+	DefinitionRangeSame       DefinitionRule = `range must start and end on different fields`
+	DefinitionRangeType       DefinitionRule = `range must start and end on fields of the same type`
+	DefinitionRangeOrderable  DefinitionRule = `range must start and end on orderable fields`
+	DefinitionObjectNotFound  DefinitionRule = `referenced object is not defined`
+	DefinitionIdentityMissing DefinitionRule = `referenced identity is not defined`
+	DefinitionValuesEmpty     DefinitionRule = `an enumeration must have values listed`
+	DefinitionValueName       DefinitionRule = `enumeration value name is not allowed or repeated`
+	DefinitionDefault         DefinitionRule = `default is not valid for the field`
+	DefinitionStructTag       DefinitionRule = `struct tag option is not valid`
+	DefinitionTranslation     DefinitionRule = `translation is missing for required languages`
+)
+
+// DefinitionError describes a rule broken by a domain definition, found at Path.
+type DefinitionError struct {
+	Path  []string
+	Rule  DefinitionRule
+	Value string // the offending value, if any
+}
+
+func NewDefinitionError[S anyString](rule DefinitionRule, value S, path ...string) DefinitionError {
+	return DefinitionError{
+		Path:  path,
+		Rule:  rule,
+		Value: string(value),
+	}
+}
+
+func (e DefinitionError) Error() string {
+	return e.Localize(plainLocalizer{})
+}
+
+func (e DefinitionError) Localize(l Localizer) string {
+	if len(e.Value) == 0 {
+		return l.Sprintf(`definition at "%s" is not valid: %s`, localizePath(l, e.Path), l.Sprintf(string(e.Rule)))
+	}
+	return l.Sprintf(`definition at "%s" is not valid: %s, got "%s"`, localizePath(l, e.Path), l.Sprintf(string(e.Rule)), e.Value)
+}
+
+// DefinitionErrors collects all DefinitionError found in one pass.
+type DefinitionErrors []DefinitionError
+
+func (e DefinitionErrors) Error() string {
+	return e.Localize(plainLocalizer{})
+}
+
+func (e DefinitionErrors) Localize(l Localizer) string {
+	return localizeAll(l, "%d definition errors: %s", e)
+}
@@ -5,6 +5,7 @@ package seederrors
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 )
 
 // SystemErrors describe internal system errors
@@ -29,7 +30,11 @@ func NewFieldNotFoundError[S anyString](fieldName S) FieldNotFoundError {
 }
 
 func (e FieldNotFoundError) Error() string {
-	return fmt.Sprintf(`field "%s" is not found`, e.FieldName)
+	return e.Localize(plainLocalizer{})
+}
+
+func (e FieldNotFoundError) Localize(l Localizer) string {
+	return l.Sprintf(`field "%s" is not found`, l.Name(e.FieldName))
 }
 
 type ValueRequiredError struct {
@@ -41,7 +46,11 @@ func NewValueRequiredError[S anyString](fieldName S) ValueRequiredError {
 }
 
 func (e ValueRequiredError) Error() string {
-	return fmt.Sprintf(`field "%s" is required`, e.FieldName)
+	return e.Localize(plainLocalizer{})
+}
+
+func (e ValueRequiredError) Localize(l Localizer) string {
+	return l.Sprintf(`field "%s" is required`, l.Name(e.FieldName))
 }
 
 type ObjectNotFoundError struct {
@@ -53,7 +62,11 @@ func NewObjectNotFoundError[S anyString](objectName S) ObjectNotFoundError {
 }
 
 func (e ObjectNotFoundError) Error() string {
-	return fmt.Sprintf(`object "%s" is not found`, e.ObjectName)
+	return e.Localize(plainLocalizer{})
+}
+
+func (e ObjectNotFoundError) Localize(l Localizer) string {
+	return l.Sprintf(`object "%s" is not found`, l.Name(e.ObjectName))
 }
 
 type TargetValueTypeNotSupportedError struct {
@@ -71,7 +84,11 @@ func NewTargetValueTypeNotSupportedError[S anyString](fieldName S, value, target
 }
 
 func (e TargetValueTypeNotSupportedError) Error() string {
-	return fmt.Sprintf(`field "%s" can not value convert from %T to %T`, e.FieldName, e.Value, e.Target)
+	return e.Localize(plainLocalizer{})
+}
+
+func (e TargetValueTypeNotSupportedError) Localize(l Localizer) string {
+	return l.Sprintf(`field "%s" can not value convert from %T to %T`, l.Name(e.FieldName), e.Value, e.Target)
 }
 
 type ThingType string
@@ -80,6 +97,9 @@ const (
 	ThingTypeDomain ThingType = "domain"
 	ThingTypeObject ThingType = "object"
 	ThingTypeField  ThingType = "field"
+
+	ThingTypeIdentity ThingType = "identity"
+	ThingTypeRange    ThingType = "range"
 )
 
 type CodeNameExistsError struct {
@@ -95,16 +115,20 @@ func NewCodeNameExistsError[S1, S2 anyString](codeName S1, t ThingType, path ...
 	}
 	return CodeNameExistsError{
 		CodeName: string(codeName),
-		Type:     ThingTypeDomain,
+		Type:     t,
 		Path:     p,
 	}
 }
 
 func (e CodeNameExistsError) Error() string {
+	return e.Localize(plainLocalizer{})
+}
+
+func (e CodeNameExistsError) Localize(l Localizer) string {
 	if len(e.Path) == 0 {
-		return fmt.Sprintf(`name "%s" in "%ss" already exists`, e.CodeName, e.Type)
+		return l.Sprintf(`name "%s" in "%ss" already exists`, e.CodeName, l.Sprintf(string(e.Type)))
 	}
-	return fmt.Sprintf(`name "%s" in "%ss" of "%s" already exists`, e.CodeName, e.Type, strings.Join(e.Path, "."))
+	return l.Sprintf(`name "%s" in "%ss" of "%s" already exists`, e.CodeName, l.Sprintf(string(e.Type)), localizePath(l, e.Path))
 }
 
 type FieldNotSupportedError struct {
@@ -129,10 +153,14 @@ func NewFieldNotSupportedError[S1 anyString](fieldTypeName string, fieldName S1,
 }
 
 func (e FieldNotSupportedError) Error() string {
+	return e.Localize(plainLocalizer{})
+}
+
+func (e FieldNotSupportedError) Localize(l Localizer) string {
 	if len(e.Value) == 0 && len(e.Path) == 0 {
-		return fmt.Sprintf(`field "%s" of "%s" is not supported`, e.FieldName, e.FieldTypeName)
+		return l.Sprintf(`field "%s" of "%s" is not supported`, l.Name(e.FieldName), e.FieldTypeName)
 	}
-	return fmt.Sprintf(`setting "%s" to "%s" in field "%s" of "%s" is not supported`, strings.Join(e.Path, "."), e.Value, e.FieldName, e.FieldTypeName)
+	return l.Sprintf(`setting "%s" to "%s" in field "%s" of "%s" is not supported`, strings.Join(e.Path, "."), e.Value, l.Name(e.FieldName), e.FieldTypeName)
 }
 
 type FieldsNotDefinedError struct {
@@ -146,5 +174,62 @@ func NewFieldsNotDefinedError(of string) FieldsNotDefinedError {
 }
 
 func (e FieldsNotDefinedError) Error() string {
-	return fmt.Sprintf(`"%s" has an emply field list`, e.Of)
+	return e.Localize(plainLocalizer{})
+}
+
+func (e FieldsNotDefinedError) Localize(l Localizer) string {
+	return l.Sprintf(`"%s" has an emply field list`, l.Name(e.Of))
+}
+
+type UnknownNameError struct {
+	Of   string
+	Name string
+}
+
+// NewUnknownNameError is used when decoding an enumerated name, such as a field type, that is not known.
+func NewUnknownNameError(of, name string) UnknownNameError {
+	return UnknownNameError{
+		Of:   of,
+		Name: name,
+	}
+}
+
+func (e UnknownNameError) Error() string {
+	return e.Localize(plainLocalizer{})
+}
+
+func (e UnknownNameError) Localize(l Localizer) string {
+	return l.Sprintf(`"%s" is not a known %s`, e.Name, e.Of)
+}
+
+type SyntaxError struct {
+	Offset  int // byte offset in the input, starting from 0
+	Line    int // line number, starting from 1
+	Column  int // column in runes, starting from 1
+	Message string
+}
+
+// NewSyntaxError is used when parsing text, such as a condition, with the position of the error in input.
+func NewSyntaxError(input string, offset int, format string, a ...any) SyntaxError {
+	if offset > len(input) {
+		offset = len(input)
+	}
+	before := input[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := utf8.RuneCountInString(before[strings.LastIndex(before, "\n")+1:]) + 1
+	return SyntaxError{
+		Offset:  offset,
+		Line:    line,
+		Column:  column,
+		Message: fmt.Sprintf(format, a...),
+	}
+}
+
+func (e SyntaxError) Error() string {
+	return e.Localize(plainLocalizer{})
+}
+
+// Localize does not translate Message, which describes the input.
+func (e SyntaxError) Localize(l Localizer) string {
+	return l.Sprintf(`syntax error at line %d column %d: %s`, e.Line, e.Column, e.Message)
 }
@@ -0,0 +1,155 @@
+package seederrors
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+// Localizer renders errors for users of one language.
+type Localizer interface {
+	// Sprintf formats a message of Catalog, keyed by its English format string.
+	Sprintf(key string, a ...any) string
+	// Name returns the display name of a code name, such as the label of a field.
+	Name(codeName string) string
+}
+
+// LocalizedError is an error that can be rendered by a Localizer.
+// Error returns the same message as Localize with English messages and code names.
+type LocalizedError interface {
+	error
+	Localize(l Localizer) string
+}
+
+// Catalog holds the translations of error messages and rules, keyed by their English text.
+// Messages missing from a language are shown in English.
+//
+// More languages can be added with Catalog.SetString before errors are localized.
+var Catalog = catalog.NewBuilder(catalog.Fallback(language.English))
+
+// plainLocalizer renders English messages with code names, for Error.
+type plainLocalizer struct{}
+
+func (plainLocalizer) Sprintf(key string, a ...any) string {
+	return fmt.Sprintf(key, a...)
+}
+
+func (plainLocalizer) Name(codeName string) string {
+	return codeName
+}
+
+// localizePath returns path joined by ".", with each element as named by l.
+func localizePath(l Localizer, path []string) string {
+	names := make([]string, len(path))
+	for i, p := range path {
+		names[i] = l.Name(p)
+	}
+	return strings.Join(names, ".")
+}
+
+// localizeAll renders a collection of errors, format is given the count and the joined messages.
+func localizeAll[E LocalizedError](l Localizer, format string, errs []E) string {
+	ss := make([]string, len(errs))
+	for i, err := range errs {
+		ss[i] = err.Localize(l)
+	}
+	return l.Sprintf(format, len(errs), strings.Join(ss, "; "))
+}
+
+func init() {
+	for key, msg := range _zhMessages {
+		if err := Catalog.SetString(language.Chinese, key, msg); err != nil {
+			panic(err)
+		}
+	}
+}
+
+var _zhMessages = map[string]string{
+	// messages
+	`field "%s" is not found`:                                                 `找不到字段“%s”`,
+	`field "%s" is required`:                                                  `字段“%s”是必填的`,
+	`object "%s" is not found`:                                                `找不到对象“%s”`,
+	`field "%s" can not value convert from %T to %T`:                          `字段“%s”的值不能从 %T 转换为 %T`,
+	`name "%s" in "%ss" already exists`:                                       `名称“%[1]s”在%[2]s中已存在`,
+	`name "%s" in "%ss" of "%s" already exists`:                               `名称“%[1]s”在“%[3]s”的%[2]s中已存在`,
+	`field "%s" of "%s" is not supported`:                                     `不支持%[2]s类型的字段“%[1]s”`,
+	`setting "%s" to "%s" in field "%s" of "%s" is not supported`:             `不支持将%[4]s类型的字段“%[3]s”的设置“%[1]s”设为“%[2]s”`,
+	`"%s" has an emply field list`:                                            `“%s”的字段列表为空`,
+	`"%s" is not a known %s`:                                                  `“%[1]s”不是已知的%[2]s`,
+	`syntax error at line %d column %d: %s`:                                   `第 %d 行第 %d 列有语法错误：%s`,
+	`code name "%s" is not allowed: %s`:                                       `代码名称“%s”不被允许：%s`,
+	`code name "%s" already exists as "%s" and have the same version postfix`: `代码名称“%s”已存在为“%s”，且版本后缀相同`,
+	`code name "%s" is a prefix of "%s" `:                                     `代码名称“%s”是“%s”的前缀`,
+	`definition at "%s" is not valid: %s`:                                     `“%s”处的定义无效：%s`,
+	`definition at "%s" is not valid: %s, got "%s"`:                           `“%s”处的定义无效：%s，得到“%s”`,
+	`%d definition errors: %s`:                                                `%d 个定义错误：%s`,
+	`value "%s" is not valid: %s`:                                             `值“%s”无效：%s`,
+	`value "%s" at "%s" is not valid: %s`:                                     `“%[2]s”处的值“%[1]s”无效：%[3]s`,
+	`%d value errors: %s`:                                                     `%d 个值错误：%s`,
+	`query at "%s" is not valid: %s`:                                          `“%s”处的查询无效：%s`,
+	`query at "%s" is not valid: %s, got "%s"`:                                `“%s”处的查询无效：%s，得到“%s”`,
+	`%d query errors: %s`:                                                     `%d 个查询错误：%s`,
+
+	// thing types
+	string(ThingTypeDomain):   `领域`,
+	string(ThingTypeObject):   `对象`,
+	string(ThingTypeField):    `字段`,
+	string(ThingTypeIdentity): `标识`,
+	string(ThingTypeRange):    `范围`,
+
+	// name rules
+	string(NameEmpty):         `名称不能为空`,
+	string(NameUnderline):     `不能以“_”开头或结尾，也不能有连续的“_”`,
+	string(NameCharacter):     `只允许字母 [a-zA-Z]、数字 [0-9] 或“_”，且必须以字母开头`,
+	string(NameVersion):       `类似版本号的字符序列不能出现在名称的开头或中间，且不能以 0 开头`,
+	string(NameVersionNumber): `版本号必须在 2 到 99 之间`,
+
+	// definition rules
+	string(DefinitionFieldType):       `字段类型无效`,
+	string(DefinitionSettingType):     `字段类型设置与字段类型不符`,
+	string(DefinitionSetting):         `字段类型设置无效`,
+	string(DefinitionFieldNotFound):   `字段未定义`,
+	string(DefinitionIdentityEmpty):   `标识必须列出字段`,
+	string(DefinitionRangeSame):       `范围的起止必须是不同的字段`,
+	string(DefinitionRangeType):       `范围的起止必须是相同类型的字段`,
+	string(DefinitionRangeOrderable):  `范围的起止必须是可排序的字段`,
+	string(DefinitionObjectNotFound):  `引用的对象未定义`,
+	string(DefinitionIdentityMissing): `引用的标识未定义`,
+	string(DefinitionValuesEmpty):     `枚举必须列出值`,
+	string(DefinitionValueName):       `枚举值的名称不被允许或重复`,
+	string(DefinitionDefault):         `默认值对该字段无效`,
+	string(DefinitionStructTag):       `结构体标签选项无效`,
+	string(DefinitionTranslation):     `缺少所需语言的翻译`,
+
+	// value rules, ValueFieldNotFound is the same as DefinitionFieldNotFound
+	string(ValueRequired):   `值是必填的`,
+	string(ValueType):       `值的类型与字段类型不符`,
+	string(ValueTooShort):   `值比允许的短`,
+	string(ValueTooLong):    `值比允许的长`,
+	string(ValueMultiline):  `值必须是单行`,
+	string(ValueTooSmall):   `值比允许的小`,
+	string(ValueTooLarge):   `值比允许的大`,
+	string(ValuePrecision):  `值比允许的精度更高`,
+	string(ValueTimeZone):   `值必须是 UTC 时间`,
+	string(ValueNotAllowed): `值不是允许的值之一`,
+	string(ValueRepeated):   `值在唯一值列表中重复`,
+	string(ValueRangeOrder): `范围的结束在开始之前`,
+	string(ValueUnit):       `值的单位无法转换`,
+
+	// query rules, QueryFieldNotFound is the same as DefinitionFieldNotFound
+	string(QueryDomain):          `查询的领域与该领域不符`,
+	string(QueryObjectNotFound):  `查询的对象未定义`,
+	string(QueryPathNotWalkable): `路径越过了既不是引用也不是组合的字段`,
+	string(QueryOperator):        `运算符无效`,
+	string(QueryOperandType):     `操作数类型与运算符不符`,
+	string(QueryLiteralType):     `字面量类型与字段类型不符`,
+	string(QueryNotOrderable):    `字段不可排序`,
+	string(QueryNegative):        `值不能为负`,
+	string(QueryCursor):          `游标对该查询无效`,
+	string(QueryGroupBy):         `字段不能分组`,
+	string(QueryNotGrouped):      `字段未分组或聚合`,
+	string(QueryAggregateName):   `聚合名称缺失、重复或被字段使用`,
+	string(QueryAggregate):       `聚合函数不适用于该字段`,
+}
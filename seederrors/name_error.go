@@ -1,7 +1,5 @@
 package seederrors
 
-import "fmt"
-
 type NameRule string
 
 const (
@@ -27,7 +25,11 @@ func NewNameNotAllowedError[S ~string](name S, rule NameRule, pos ...[]int) Name
 }
 
 func (e NameNotAllowedError) Error() string {
-	return fmt.Sprintf(`code name "%s" is not allowed: %s`, e.OnInput, e.Rule)
+	return e.Localize(plainLocalizer{})
+}
+
+func (e NameNotAllowedError) Localize(l Localizer) string {
+	return l.Sprintf(`code name "%s" is not allowed: %s`, e.OnInput, l.Sprintf(string(e.Rule)))
 }
 
 type NameRepeatedError struct {
@@ -52,8 +54,12 @@ func NewNameVersionRepeatedError[S ~string](n1, n2 S, version int8) NameRepeated
 }
 
 func (e NameRepeatedError) Error() string {
+	return e.Localize(plainLocalizer{})
+}
+
+func (e NameRepeatedError) Localize(l Localizer) string {
 	if e.Version > 0 {
-		return fmt.Sprintf(`code name "%s" already exists as "%s" and have the same version postfix`, e.Short, e.Long)
+		return l.Sprintf(`code name "%s" already exists as "%s" and have the same version postfix`, e.Short, e.Long)
 	}
-	return fmt.Sprintf(`code name "%s" is a prefix of "%s" `, e.Short, e.Long)
+	return l.Sprintf(`code name "%s" is a prefix of "%s" `, e.Short, e.Long)
 }
@@ -0,0 +1,62 @@
+package seederrors
+
+import "fmt"
+
+type QueryRule string
+
+const (
+	QueryDomain          QueryRule = `queried domain does not match the domain`
+	QueryObjectNotFound  QueryRule = `queried object is not defined`
+	QueryFieldNotFound   QueryRule = `field is not defined`
+	QueryPathNotWalkable QueryRule = `path continues past a field that is not a reference or combination`
+	QueryOperator        QueryRule = `operator is not valid`
+	QueryOperandType     QueryRule = `operand type does not match operator`
+	QueryLiteralType     QueryRule = `literal type does not match field type`
+	QueryNotOrderable    QueryRule = `field is not orderable`
+	QueryNegative        QueryRule = `value must not be negative`
+	QueryCursor          QueryRule = `cursor is not valid for the query`
+	QueryGroupBy         QueryRule = `field can not be grouped`
+	QueryNotGrouped      QueryRule = `field is not grouped or aggregated`
+	QueryAggregateName   QueryRule = `aggregate name is missing, repeated, or used by a field`
+	QueryAggregate       QueryRule = `aggregate function does not apply to the field`
+)
+
+// QueryError describes a rule broken by a query, found at Path.
+//
+// Path starts with the part of the query, such as Fields, Order or Condition, followed by indexes of
+// orders and child conditions, and the field path up to the offending name.
+type QueryError struct {
+	Path  []string
+	Rule  QueryRule
+	Value string // the offending value, formatted for display
+}
+
+func NewQueryError(rule QueryRule, value any, path ...string) QueryError {
+	return QueryError{
+		Path:  path,
+		Rule:  rule,
+		Value: fmt.Sprint(value),
+	}
+}
+
+func (e QueryError) Error() string {
+	return e.Localize(plainLocalizer{})
+}
+
+func (e QueryError) Localize(l Localizer) string {
+	if len(e.Value) == 0 {
+		return l.Sprintf(`query at "%s" is not valid: %s`, localizePath(l, e.Path), l.Sprintf(string(e.Rule)))
+	}
+	return l.Sprintf(`query at "%s" is not valid: %s, got "%s"`, localizePath(l, e.Path), l.Sprintf(string(e.Rule)), e.Value)
+}
+
+// QueryErrors collects all QueryError found in one check.
+type QueryErrors []QueryError
+
+func (e QueryErrors) Error() string {
+	return e.Localize(plainLocalizer{})
+}
+
+func (e QueryErrors) Localize(l Localizer) string {
+	return localizeAll(l, "%d query errors: %s", e)
+}
@@ -0,0 +1,59 @@
+package seederrors
+
+import "fmt"
+
+type ValueRule string
+
+const (
+	ValueRequired      ValueRule = `value is required`
+	ValueFieldNotFound ValueRule = `field is not defined`
+	ValueType          ValueRule = `value type does not match field type`
+	ValueTooShort      ValueRule = `value is shorter than allowed`
+	ValueTooLong       ValueRule = `value is longer than allowed`
+	ValueMultiline     ValueRule = `value must be a single line`
+	ValueTooSmall      ValueRule = `value is smaller than allowed`
+	ValueTooLarge      ValueRule = `value is larger than allowed`
+	ValuePrecision     ValueRule = `value is more precise than allowed`
+	ValueTimeZone      ValueRule = `value must be in UTC`
+	ValueNotAllowed    ValueRule = `value is not one of the allowed values`
+	ValueRepeated      ValueRule = `value is repeated in a list of unique values`
+	ValueRangeOrder    ValueRule = `range end is before range start`
+	ValueUnit          ValueRule = `value unit can not be converted`
+)
+
+// ValueError describes a rule broken by a value, found at Path.
+type ValueError struct {
+	Path  []string
+	Rule  ValueRule
+	Value string // the offending value, formatted for display
+}
+
+func NewValueError(rule ValueRule, value any, path ...string) ValueError {
+	return ValueError{
+		Path:  path,
+		Rule:  rule,
+		Value: fmt.Sprint(value),
+	}
+}
+
+func (e ValueError) Error() string {
+	return e.Localize(plainLocalizer{})
+}
+
+func (e ValueError) Localize(l Localizer) string {
+	if len(e.Path) == 0 {
+		return l.Sprintf(`value "%s" is not valid: %s`, e.Value, l.Sprintf(string(e.Rule)))
+	}
+	return l.Sprintf(`value "%s" at "%s" is not valid: %s`, e.Value, localizePath(l, e.Path), l.Sprintf(string(e.Rule)))
+}
+
+// ValueErrors collects all ValueError found in one check.
+type ValueErrors []ValueError
+
+func (e ValueErrors) Error() string {
+	return e.Localize(plainLocalizer{})
+}
+
+func (e ValueErrors) Localize(l Localizer) string {
+	return localizeAll(l, "%d value errors: %s", e)
+}
@@ -1,7 +1,7 @@
 package seedfake
 
 import (
-	"time"
+	"github.com/shopspring/decimal"
 
 	"github.com/xiegeo/seed"
 	"github.com/xiegeo/seed/seederrors"
@@ -37,20 +37,41 @@ func (g *ValueGen) ValueForSetting(s seed.FieldTypeSetting) (any, error) {
 		}
 		return g.RangeBigInt(vt.Min, vt.Max), nil
 	case seed.RealSetting:
-		if vt.Standard == seed.Float64 {
-			return g.RangeFloat64(*vt.MinFloat, *vt.MaxFloat), nil
-		}
-		return nil, seederrors.NewSystemError("ValueForSetting RealSetting.Standard %s not yet supported", vt.Standard)
+		return g.realForSetting(vt)
 	case seed.ReferenceSetting:
 		return nil, seederrors.NewSystemError("ValueForSetting FieldTypeSetting ReferenceSetting not yet supported")
 	case seed.ListSetting:
 		return g.ValuesForSetting(vt.ItemTypeSetting, g.RangeInt64(vt.MinLength, vt.MaxLength))
 	case seed.CombinationSetting:
 		return g.MapForFieldGroup(&vt)
+	case seed.EnumerationSetting:
+		return g.enumerationForSetting(vt)
 	}
 	return nil, seederrors.NewSystemError("ValueForSetting FieldTypeSetting type=%T not handled", s)
 }
 
+func (g *ValueGen) realForSetting(s seed.RealSetting) (any, error) {
+	if !s.Valid() {
+		return nil, seederrors.NewSystemError("ValueForSetting RealSetting is not valid")
+	}
+	switch {
+	case s.Standard == seed.Float64:
+		return g.RangeFloat64(*s.MinFloat, *s.MaxFloat), nil
+	case s.Standard.IsDecimal():
+		coefficient := g.RangeBigInt(s.MinMantissa, s.MaxMantissa)
+		exponent := g.RangeInt64(*s.MinExponent, *s.MaxExponent)
+		return decimal.NewFromBigInt(coefficient, int32(exponent)), nil
+	}
+	return nil, seederrors.NewSystemError("ValueForSetting RealSetting.Standard %s not yet supported", s.Standard)
+}
+
+func (g *ValueGen) enumerationForSetting(s seed.EnumerationSetting) (any, error) {
+	if len(s.Values) == 0 {
+		return nil, seederrors.NewSystemError("ValueForSetting EnumerationSetting has no values")
+	}
+	return pickFromSlice(g, s.Values).Name, nil
+}
+
 func (g *ValueGen) MapForFieldGroup(fg seed.FieldGroupGetter) (map[seed.CodeName]any, error) {
 	out := make(map[seed.CodeName]any, fg.GetFields().Count())
 	err := fg.GetFields().RangeLogical(func(cn seed.CodeName, f *seed.Field) (err error) {
@@ -64,11 +85,11 @@ func (g *ValueGen) MapForFieldGroup(fg seed.FieldGroupGetter) (map[seed.CodeName
 	err = seed.RangeRanges(fg, func(r seed.Range) error {
 		start := out[r.Start]
 		end := out[r.End]
-		isLess, err2 := less(end, start)
+		cmp, err2 := seed.CompareValues(end, start)
 		if err2 != nil {
 			return err2
 		}
-		if isLess {
+		if cmp < 0 {
 			out[r.Start], out[r.End] = end, start
 		}
 		return nil
@@ -76,23 +97,6 @@ func (g *ValueGen) MapForFieldGroup(fg seed.FieldGroupGetter) (map[seed.CodeName
 	return out, err
 }
 
-//nolint:forcetypeassert // use recover
-func less(a, b any) (less bool, err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			err = seederrors.CombineErrors(seederrors.NewSystemError(
-				"recovered=%v", r), err)
-		}
-	}()
-	switch at := a.(type) {
-	case time.Time:
-		return at.Before(b.(time.Time)), nil
-	case int64:
-		return at < b.(int64), nil
-	}
-	return false, seederrors.NewSystemError("comparison between %T and %T not implemented", a, b)
-}
-
 func (g *ValueGen) ValuesForSetting(s seed.FieldTypeSetting, length int64) ([]any, error) {
 	out := make([]any, length)
 	for i := range out {
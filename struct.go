@@ -0,0 +1,404 @@
+package seed
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/language"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// StructTag is the struct tag key read by ObjectFromStruct and ValuesFromStruct.
+//
+// The tag value is a comma separated list, starting with the field name, followed by options:
+//
+//	Name      string    `seed:"display_name,label=Display Name,max=100,singleline"`
+//	Email     string    `seed:",identity=email,max=200"`
+//	Age       *int      `seed:",min=0,max=150"` // pointers are nullable
+//	Role      string    `seed:",enum=admin|user"`
+//	CreatedAt time.Time `seed:",scale=1s,tz"`
+//	Internal  string    `seed:"-"` // skipped
+//
+// Options are:
+//
+//   - label, description: English label and description.
+//   - min, max: bounds of numbers and time stamps (RFC 3339), or lengths of strings, binaries and lists.
+//     Bounds default to the range of the Go type, or StructBigIntDigits for *big.Int.
+//   - nullable: a value type that is nullable, pointers are always nullable.
+//   - identity: member of the unnamed identity, or of a named identity by identity=name.
+//   - singleline: strings must be single line.
+//   - enum: enumeration values separated by "|", for string and CodeName types.
+//   - scale, tz: TimeStampSetting.Scale as a duration, and WithTimeZoneOffset.
+//
+// Names default to the Go name in snake case.
+const StructTag = "seed"
+
+// Defaults used by ObjectFromStruct when bounds are not given by tags.
+const (
+	StructMaxLength    = 1 << 20 // max code points for strings, bytes for binaries, and items in lists.
+	StructBigIntDigits = 38      // max decimal digits of *big.Int, both positive and negative.
+)
+
+var (
+	_structTimeMax = time.Date(10000, 1, 1, 0, 0, 0, -1, time.UTC)
+
+	_typeTime     = reflect.TypeOf(time.Time{})
+	_typeBigInt   = reflect.TypeOf(&big.Int{})
+	_typeDecimal  = reflect.TypeOf(decimal.Decimal{})
+	_typeBytes    = reflect.TypeOf([]byte{})
+	_typeCodeName = reflect.TypeOf(CodeName(""))
+)
+
+// ObjectFromStruct builds an object from the exported fields of a Go struct, v is a struct or a pointer to one.
+// The object is named by the type name in snake case. Nested structs are combinations, and slices are lists.
+// See StructTag for supported tags.
+func ObjectFromStruct(v any) (*Object, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, seederrors.NewSystemError("ObjectFromStruct expects a struct, got %T", v)
+	}
+	g, err := fieldGroupFromStruct(t, snakeCase(t.Name()))
+	if err != nil {
+		return nil, err
+	}
+	return &Object{
+		Thing:      Thing{Name: CodeName(snakeCase(t.Name()))},
+		FieldGroup: g,
+	}, nil
+}
+
+type structTag struct {
+	name    CodeName
+	options map[string]string
+}
+
+func parseStructTag(sf reflect.StructField) (structTag, bool) {
+	tag, ok := sf.Tag.Lookup(StructTag)
+	if tag == "-" || !sf.IsExported() {
+		return structTag{}, false
+	}
+	out := structTag{options: make(map[string]string)}
+	if !ok {
+		out.name = CodeName(snakeCase(sf.Name))
+		return out, true
+	}
+	parts := strings.Split(tag, ",")
+	out.name = CodeName(parts[0])
+	if out.name == "" {
+		out.name = CodeName(snakeCase(sf.Name))
+	}
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		out.options[strings.TrimSpace(k)] = v
+	}
+	return out, true
+}
+
+// structFields calls f for each exported field of struct type t not skipped by tags.
+func structFields(t reflect.Type, f func(i int, sf reflect.StructField, tag structTag) error) error {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := parseStructTag(sf)
+		if !ok {
+			continue
+		}
+		err := f(i, sf, tag)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fieldGroupFromStruct(t reflect.Type, path ...string) (FieldGroup, error) {
+	fields := NewFields0[*Field]()
+	var identities []Identity
+	err := structFields(t, func(_ int, sf reflect.StructField, tag structTag) error {
+		f, err := fieldFromStruct(sf.Type, tag, withPath(path, string(tag.name))...)
+		if err != nil {
+			return err
+		}
+		err = fields.AddValue(f)
+		if err != nil {
+			return err
+		}
+		if id, ok := tag.options["identity"]; ok {
+			identities = addToIdentity(identities, CodeName(id), tag.name)
+		}
+		return nil
+	})
+	return FieldGroup{Fields: fields, Identities: identities}, err
+}
+
+func addToIdentity(ids []Identity, name, field CodeName) []Identity {
+	for i := range ids {
+		if ids[i].Name == name {
+			ids[i].Fields = append(ids[i].Fields, field)
+			return ids
+		}
+	}
+	return append(ids, Identity{Thing: Thing{Name: name}, Fields: []CodeName{field}})
+}
+
+func fieldFromStruct(t reflect.Type, tag structTag, path ...string) (*Field, error) {
+	f := &Field{Thing: Thing{Name: tag.name}}
+	if label, ok := tag.options["label"]; ok {
+		f.Label = I18n[string]{language.English: label}
+	}
+	if description, ok := tag.options["description"]; ok {
+		f.Description = I18n[string]{language.English: description}
+	}
+	_, f.Nullable = tag.options["nullable"]
+	if t.Kind() == reflect.Pointer {
+		f.Nullable = true
+		if t != _typeBigInt {
+			t = t.Elem()
+		}
+	}
+	var err error
+	f.FieldType, f.FieldTypeSetting, err = settingFromType(t, tag, path...)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func tagError(option string, tag structTag, path []string) error {
+	return seederrors.NewDefinitionError(seederrors.DefinitionStructTag, tag.options[option], withPath(path, option)...)
+}
+
+//nolint:cyclop // one case per supported go type
+func settingFromType(t reflect.Type, tag structTag, path ...string) (FieldType, FieldTypeSetting, error) {
+	if enum, ok := tag.options["enum"]; ok {
+		if t.Kind() != reflect.String {
+			return 0, nil, tagError("enum", tag, path)
+		}
+		var s EnumerationSetting
+		for _, v := range strings.Split(enum, "|") {
+			s.Values = append(s.Values, EnumerationValue{Thing: Thing{Name: CodeName(v)}})
+		}
+		return Enumeration, s, nil
+	}
+	minLength, maxLength, err := lengthBounds(tag, path)
+	switch {
+	case t == _typeTime:
+		return timeStampFromTag(tag, path)
+	case t == _typeBigInt:
+		limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(StructBigIntDigits), nil)
+		limit.Sub(limit, big.NewInt(1))
+		return integerBounds(IntegerSetting{Min: new(big.Int).Neg(limit), Max: limit}, tag, path)
+	case t == _typeDecimal:
+		return Real, RealSetting{Standard: Decimal64}, nil
+	case t == _typeBytes:
+		return Binary, BinarySetting{MinBytes: minLength, MaxBytes: maxLength}, err
+	}
+	switch t.Kind() {
+	case reflect.String:
+		_, singleLine := tag.options["singleline"]
+		return String, StringSetting{MinCodePoints: minLength, MaxCodePoints: maxLength, IsSingleLine: singleLine}, err
+	case reflect.Bool:
+		return Boolean, BooleanSetting{}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return integerFromTag(t, tag, path)
+	case reflect.Float32, reflect.Float64:
+		return realFromTag(t, tag, path)
+	case reflect.Struct:
+		g, err2 := fieldGroupFromStruct(t, path...)
+		return Combination, g, err2
+	case reflect.Slice, reflect.Array:
+		item := structTag{name: tag.name, options: map[string]string{}}
+		itemType, itemSetting, err2 := settingFromType(t.Elem(), item, withPath(path, "item")...)
+		if err2 != nil {
+			return 0, nil, err2
+		}
+		return List, ListSetting{
+			MinLength:       minLength,
+			MaxLength:       maxLength,
+			IsOrdered:       true,
+			ItemType:        itemType,
+			ItemTypeSetting: itemSetting,
+		}, err
+	}
+	return 0, nil, seederrors.NewFieldNotSupportedError(t.String(), CodeName(strings.Join(path, ".")))
+}
+
+func lengthBounds(tag structTag, path []string) (min, max int64, err error) {
+	max = StructMaxLength
+	if v, ok := tag.options["min"]; ok {
+		min, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, 0, tagError("min", tag, path)
+		}
+	}
+	if v, ok := tag.options["max"]; ok {
+		max, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, 0, tagError("max", tag, path)
+		}
+	}
+	return min, max, nil
+}
+
+func timeStampFromTag(tag structTag, path []string) (FieldType, FieldTypeSetting, error) {
+	s := TimeStampSetting{Max: _structTimeMax, Scale: time.Nanosecond}
+	_, s.WithTimeZoneOffset = tag.options["tz"]
+	var err error
+	if v, ok := tag.options["min"]; ok {
+		if s.Min, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return 0, nil, tagError("min", tag, path)
+		}
+	}
+	if v, ok := tag.options["max"]; ok {
+		if s.Max, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return 0, nil, tagError("max", tag, path)
+		}
+	}
+	if v, ok := tag.options["scale"]; ok {
+		if s.Scale, err = time.ParseDuration(v); err != nil {
+			return 0, nil, tagError("scale", tag, path)
+		}
+	}
+	return TimeStamp, s, nil
+}
+
+func integerFromTag(t reflect.Type, tag structTag, path []string) (FieldType, FieldTypeSetting, error) {
+	var s IntegerSetting
+	bits := uint(t.Bits())
+	if t.Kind() >= reflect.Uint && t.Kind() <= reflect.Uint64 {
+		s.Min = new(big.Int)
+		s.Max = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), bits), big.NewInt(1))
+	} else {
+		s.Min = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), bits-1))
+		s.Max = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), bits-1), big.NewInt(1))
+	}
+	return integerBounds(s, tag, path)
+}
+
+// integerBounds sets the bounds of s that are given by tag.
+func integerBounds(s IntegerSetting, tag structTag, path []string) (FieldType, FieldTypeSetting, error) {
+	if v, ok := tag.options["min"]; ok {
+		if _, ok := s.Min.SetString(v, 10); !ok {
+			return 0, nil, tagError("min", tag, path)
+		}
+	}
+	if v, ok := tag.options["max"]; ok {
+		if _, ok := s.Max.SetString(v, 10); !ok {
+			return 0, nil, tagError("max", tag, path)
+		}
+	}
+	return Integer, s, nil
+}
+
+func realFromTag(t reflect.Type, tag structTag, path []string) (FieldType, FieldTypeSetting, error) {
+	s := RealSetting{Standard: Float64}
+	if t.Kind() == reflect.Float32 {
+		s.Standard = Float32
+	}
+	for _, option := range []struct {
+		name  string
+		value **float64
+	}{{"min", &s.MinFloat}, {"max", &s.MaxFloat}} {
+		v, ok := tag.options[option.name]
+		if !ok {
+			continue
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || math.IsNaN(f) {
+			return 0, nil, tagError(option.name, tag, path)
+		}
+		*option.value = &f
+	}
+	return Real, s, nil
+}
+
+// ValuesFromStruct converts a struct, as described by ObjectFromStruct, to values keyed by field name.
+func ValuesFromStruct(v any) (map[CodeName]any, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, seederrors.NewSystemError("ValuesFromStruct expects a struct, got %T", v)
+	}
+	return valuesFromStruct(rv), nil
+}
+
+func valuesFromStruct(rv reflect.Value) map[CodeName]any {
+	out := make(map[CodeName]any)
+	_ = structFields(rv.Type(), func(i int, _ reflect.StructField, tag structTag) error {
+		out[tag.name] = valueFromReflect(rv.Field(i))
+		return nil
+	})
+	return out
+}
+
+// valueFromReflect converts Go values to the value types used by CheckValue.
+func valueFromReflect(rv reflect.Value) any {
+	t := rv.Type()
+	switch {
+	case t == _typeTime, t == _typeBigInt, t == _typeDecimal, t == _typeBytes, t == _typeCodeName:
+		if t == _typeBigInt && rv.IsNil() {
+			return nil
+		}
+		return rv.Interface()
+	}
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return nil
+		}
+		return valueFromReflect(rv.Elem())
+	case reflect.String:
+		return rv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u := rv.Uint()
+		if u > math.MaxInt64 {
+			return new(big.Int).SetUint64(u)
+		}
+		return int64(u)
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Struct:
+		return valuesFromStruct(rv)
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil
+		}
+		out := make([]any, rv.Len())
+		for i := range out {
+			out[i] = valueFromReflect(rv.Index(i))
+		}
+		return out
+	}
+	return rv.Interface()
+}
+
+// snakeCase converts Go names such as "HTTPServerID" to "http_server_id".
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && nextLower {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
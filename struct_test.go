@@ -0,0 +1,118 @@
+package seed_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
+	"golang.org/x/text/language"
+
+	. "github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+type address struct {
+	Street string `seed:",max=100"`
+	Zip    string `seed:"postal_code,max=10,singleline"`
+}
+
+type userAccount struct {
+	ID        int64     `seed:",identity"`
+	Email     string    `seed:",identity=email,label=Email Address,max=200,singleline"`
+	Age       *uint8    `seed:",max=150"`
+	Role      string    `seed:",enum=admin|user"`
+	Score     float64   `seed:",min=0,max=1"`
+	CreatedAt time.Time `seed:",scale=1s"`
+	Tags      []string  `seed:",max=3"`
+	Home      address
+	internal  string //nolint:unused // unexported fields are skipped
+	Skipped   string `seed:"-"`
+}
+
+func TestObjectFromStruct(t *testing.T) {
+	ob, err := ObjectFromStruct(&userAccount{})
+	require.NoError(t, err)
+	assert.Equal(t, CodeName("user_account"), ob.Name)
+	assert.Equal(t, []CodeName{"id", "email", "age", "role", "score", "created_at", "tags", "home"}, names(ob.Fields.Values()))
+	assert.Equal(t, []Identity{
+		{Fields: []CodeName{"id"}},
+		{Thing: Thing{Name: "email"}, Fields: []CodeName{"email"}},
+	}, ob.Identities)
+	require.NoError(t, must.V(NewDomain(Thing{Name: "test"}, ob)).Validate())
+
+	email, _ := ob.Fields.Get("email")
+	assert.Equal(t, "Email Address", email.Label[language.English])
+	assert.Equal(t, StringSetting{MaxCodePoints: 200, IsSingleLine: true}, email.FieldTypeSetting)
+	age, _ := ob.Fields.Get("age")
+	assert.True(t, age.Nullable)
+	assert.Equal(t, IntegerSetting{Min: big.NewInt(0), Max: big.NewInt(150)}, age.FieldTypeSetting)
+	role, _ := ob.Fields.Get("role")
+	assert.Equal(t, Enumeration, role.FieldType)
+	tags, _ := ob.Fields.Get("tags")
+	assert.Equal(t, List, tags.FieldType)
+	home, _ := ob.Fields.Get("home")
+	assert.Equal(t, []CodeName{"street", "postal_code"}, names(home.FieldTypeSetting.(CombinationSetting).Fields.Values()))
+
+	type badTag struct {
+		N int `seed:",max=many"`
+	}
+	_, err = ObjectFromStruct(badTag{})
+	assert.ErrorAs(t, err, &seederrors.DefinitionError{})
+	_, err = ObjectFromStruct(1)
+	assert.Error(t, err)
+}
+
+func TestObjectFromStructBigInt(t *testing.T) {
+	type account struct {
+		ID      int64
+		Balance *big.Int
+		Limit   *big.Int `seed:",min=0"`
+	}
+	ob, err := ObjectFromStruct(account{})
+	require.NoError(t, err)
+	require.NoError(t, must.V(NewDomain(Thing{Name: "test"}, ob)).Validate())
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(StructBigIntDigits), nil)
+	limit.Sub(limit, big.NewInt(1))
+	balance, _ := ob.Fields.Get("balance")
+	assert.True(t, balance.Nullable, "pointers are nullable")
+	s := balance.FieldTypeSetting.(IntegerSetting)
+	assert.Equal(t, new(big.Int).Neg(limit).String(), s.Min.String())
+	assert.Equal(t, limit.String(), s.Max.String())
+	limitField, _ := ob.Fields.Get("limit")
+	s = limitField.FieldTypeSetting.(IntegerSetting)
+	assert.Equal(t, "0", s.Min.String())
+	assert.Equal(t, limit.String(), s.Max.String())
+
+	values, err := ValuesFromStruct(account{ID: 1, Limit: big.NewInt(5)})
+	require.NoError(t, err)
+	assert.Nil(t, values["balance"])
+	assert.NoError(t, CheckValues(ob, values))
+}
+
+func TestValuesFromStruct(t *testing.T) {
+	ob := must.V(ObjectFromStruct(userAccount{}))
+	age := uint8(30)
+	values, err := ValuesFromStruct(userAccount{
+		ID:        1,
+		Email:     "a@example.com",
+		Age:       &age,
+		Role:      "admin",
+		Score:     0.5,
+		CreatedAt: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		Tags:      []string{"a"},
+		Home:      address{Street: "Main", Zip: "12345"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(30), values["age"])
+	assert.Equal(t, map[CodeName]any{"street": "Main", "postal_code": "12345"}, values["home"])
+	assert.NoError(t, CheckValues(ob, values))
+
+	values = must.V(ValuesFromStruct(&userAccount{Role: "owner"}))
+	assert.Nil(t, values["age"])
+	var errs seederrors.ValueErrors
+	require.ErrorAs(t, CheckValues(ob, values), &errs)
+	assert.Equal(t, seederrors.ValueNotAllowed, errs[0].Rule)
+}
@@ -0,0 +1,204 @@
+package seed
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/language"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// Conversion returns the factor and offset to convert values in unit u to values in unit to:
+// converted = value * factor + offset.
+//
+// A nil unit, or the same unit, converts to itself. Otherwise both units must be of the same Dimension.
+func (u *Unit) Conversion(to *Unit) (factor, offset *big.Rat, err error) {
+	if u.Covers(to) && to.Covers(u) {
+		return big.NewRat(1, 1), new(big.Rat), nil
+	}
+	if u == nil || to == nil || u.Dimension == "" || u.Dimension != to.Dimension {
+		return nil, nil, seederrors.NewValueError(seederrors.ValueUnit, unitName(u), string(unitName(to)))
+	}
+	// value * u.factor + u.offset = converted * to.factor + to.offset
+	factor = new(big.Rat).Quo(u.factor(), to.factor())
+	offset = new(big.Rat).Sub(u.offset(), to.offset())
+	offset.Quo(offset, to.factor())
+	return factor, offset, nil
+}
+
+func unitName(u *Unit) CodeName {
+	if u == nil {
+		return ""
+	}
+	return u.Name
+}
+
+func (u *Unit) factor() *big.Rat {
+	if u.Factor == nil {
+		return big.NewRat(1, 1)
+	}
+	return u.Factor
+}
+
+func (u *Unit) offset() *big.Rat {
+	if u.Offset == nil {
+		return new(big.Rat)
+	}
+	return u.Offset
+}
+
+// ConvertUnit converts v, a number in unit from, to unit to. The result keeps the type of v, which
+// can be int, int64, *big.Int, float64 or decimal.Decimal. Integers must convert to integers exactly,
+// decimals are rounded to 18 decimal places if they can not be converted exactly.
+//
+// It can be used to convert values read from a field to the unit wanted by a user.
+func ConvertUnit(v any, from, to *Unit) (any, error) {
+	v = derefValue(v)
+	if v == nil {
+		return nil, nil
+	}
+	factor, offset, err := from.Conversion(to)
+	if err != nil {
+		return nil, err
+	}
+	convert := func(r *big.Rat) *big.Rat {
+		r.Mul(r, factor)
+		return r.Add(r, offset)
+	}
+	if i, ok := toBigInt(v); ok {
+		r := convert(new(big.Rat).SetInt(i))
+		if !r.IsInt() {
+			return nil, seederrors.NewValueError(seederrors.ValuePrecision, v, string(unitName(to)))
+		}
+		if _, isBig := v.(*big.Int); isBig || !r.Num().IsInt64() {
+			return new(big.Int).Set(r.Num()), nil
+		}
+		return r.Num().Int64(), nil
+	}
+	switch vt := v.(type) {
+	case float64:
+		if math.IsInf(vt, 0) || math.IsNaN(vt) {
+			return vt, nil // factors are positive, so infinities keep their signs
+		}
+		f, _ := convert(new(big.Rat).SetFloat64(vt)).Float64()
+		return f, nil
+	case decimal.Decimal:
+		return ratToDecimal(convert(vt.Rat())), nil
+	}
+	return nil, seederrors.NewValueError(seederrors.ValueType, v, string(unitName(to)))
+}
+
+const unitDecimalPlaces = 18
+
+// ratToDecimal returns r as a decimal with the fewest decimal places needed to be exact,
+// rounded if more than unitDecimalPlaces are needed.
+func ratToDecimal(r *big.Rat) decimal.Decimal {
+	num, den := decimal.NewFromBigInt(r.Num(), 0), decimal.NewFromBigInt(r.Denom(), 0)
+	for places := int32(0); places < unitDecimalPlaces; places++ {
+		d := num.DivRound(den, places)
+		if d.Rat().Cmp(r) == 0 {
+			return d
+		}
+	}
+	return num.DivRound(den, unitDecimalPlaces)
+}
+
+// Unit returns the unit of values of f, or nil if f has no unit.
+// The units of lists are the units of their items.
+func (f *Field) Unit() *Unit {
+	return settingUnit(f.FieldTypeSetting)
+}
+
+func settingUnit(s FieldTypeSetting) *Unit {
+	switch vt := s.(type) {
+	case IntegerSetting:
+		return vt.Unit
+	case RealSetting:
+		return vt.Unit
+	case ListSetting:
+		return settingUnit(vt.ItemTypeSetting)
+	}
+	return nil
+}
+
+func (s IntegerSetting) coversConverted(s2 IntegerSetting) bool {
+	factor, offset, err := s2.Unit.Conversion(s.Unit)
+	if err != nil || !factor.IsInt() || !offset.IsInt() {
+		return false // not all integers in s2 convert to integers in s
+	}
+	convert := func(i *big.Int) *big.Rat {
+		r := new(big.Rat).SetInt(i)
+		r.Mul(r, factor)
+		return r.Add(r, offset)
+	}
+	return new(big.Rat).SetInt(s.Min).Cmp(convert(s2.Min)) <= 0 &&
+		new(big.Rat).SetInt(s.Max).Cmp(convert(s2.Max)) >= 0
+}
+
+// coversConverted checks float ranges after conversion, converting other standards is not supported.
+func (s RealSetting) coversConverted(s2 RealSetting) bool {
+	if s.Standard.usesMantissa() || s2.Standard.usesMantissa() {
+		return false
+	}
+	min, err := ConvertUnit(*s2.MinFloat, s2.Unit, s.Unit)
+	if err != nil {
+		return false
+	}
+	max, err := ConvertUnit(*s2.MaxFloat, s2.Unit, s.Unit)
+	if err != nil {
+		return false
+	}
+	return *s.MinFloat <= min.(float64) && *s.MaxFloat >= max.(float64)
+}
+
+// StandardUnits returns common units of mass, length, temperature and time, which can be converted
+// to units of the same dimension. Each call returns new units that can be changed by the caller.
+func StandardUnits() []*Unit {
+	unit := func(dimension, name CodeName, label, symbol string, factor, offset *big.Rat) *Unit {
+		return &Unit{
+			Thing:     Thing{Name: name, Label: I18n[string]{language.English: label}},
+			Symble:    symbol,
+			Dimension: dimension,
+			Factor:    factor,
+			Offset:    offset,
+		}
+	}
+	frac := func(a, b int64) *big.Rat {
+		return big.NewRat(a, b)
+	}
+	return []*Unit{
+		unit("mass", "milligram", "Milligram", "mg", frac(1, 1000), nil),
+		unit("mass", "gram", "Gram", "g", nil, nil),
+		unit("mass", "kilogram", "Kilogram", "kg", frac(1000, 1), nil),
+		unit("mass", "tonne", "Tonne", "t", frac(1000000, 1), nil),
+		unit("mass", "ounce", "Ounce", "oz", frac(28349523125, 1000000000), nil),
+		unit("mass", "pound", "Pound", "lb", frac(45359237, 100000), nil),
+		unit("length", "millimeter", "Millimeter", "mm", frac(1, 1000), nil),
+		unit("length", "centimeter", "Centimeter", "cm", frac(1, 100), nil),
+		unit("length", "meter", "Meter", "m", nil, nil),
+		unit("length", "kilometer", "Kilometer", "km", frac(1000, 1), nil),
+		unit("length", "inch", "Inch", "in", frac(254, 10000), nil),
+		unit("length", "foot", "Foot", "ft", frac(3048, 10000), nil),
+		unit("length", "mile", "Mile", "mi", frac(1609344, 1000), nil),
+		unit("temperature", "kelvin", "Kelvin", "K", nil, nil),
+		unit("temperature", "celsius", "Celsius", "°C", nil, frac(27315, 100)),
+		unit("temperature", "fahrenheit", "Fahrenheit", "°F", frac(5, 9), frac(45967, 180)),
+		unit("time", "millisecond", "Millisecond", "ms", frac(1, 1000), nil),
+		unit("time", "second", "Second", "s", nil, nil),
+		unit("time", "minute", "Minute", "min", frac(60, 1), nil),
+		unit("time", "hour", "Hour", "h", frac(3600, 1), nil),
+		unit("time", "day", "Day", "d", frac(86400, 1), nil),
+	}
+}
+
+// StandardUnit returns the standard unit by name, see StandardUnits.
+func StandardUnit(name CodeName) (*Unit, bool) {
+	for _, u := range StandardUnits() {
+		if u.Name == name {
+			return u, true
+		}
+	}
+	return nil, false
+}
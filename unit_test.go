@@ -0,0 +1,118 @@
+package seed_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
+
+	. "github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+func standardUnit(t *testing.T, name CodeName) *Unit {
+	u, ok := StandardUnit(name)
+	require.True(t, ok, name)
+	return u
+}
+
+func TestConvertUnit(t *testing.T) {
+	g, kg := standardUnit(t, "gram"), standardUnit(t, "kilogram")
+	c, f := standardUnit(t, "celsius"), standardUnit(t, "fahrenheit")
+
+	assert.Equal(t, int64(2000), must.V(ConvertUnit(2, kg, g)))
+	assert.Equal(t, int64(2000), must.V(ConvertUnit(int64(2), kg, g)))
+	assert.Equal(t, big.NewInt(2), must.V(ConvertUnit(big.NewInt(2000), g, kg)), "*big.Int stays *big.Int")
+	_, err := ConvertUnit(int64(1500), g, kg)
+	assert.ErrorAs(t, err, &seederrors.ValueError{}, "integers must convert exactly")
+	assert.Equal(t, "1.5", must.V(ConvertUnit(must.V(decimal.NewFromString("1500")), g, kg)).(decimal.Decimal).String())
+	assert.Equal(t, "0", must.V(ConvertUnit(must.V(decimal.NewFromString("32")), f, c)).(decimal.Decimal).String())
+	assert.InDelta(t, 212.0, must.V(ConvertUnit(100.0, c, f)), 1e-9)
+	assert.InDelta(t, -40.0, must.V(ConvertUnit(-40.0, f, c)), 1e-9)
+	assert.InDelta(t, 1.0, must.V(ConvertUnit(1000.0, standardUnit(t, "meter"), standardUnit(t, "kilometer"))), 1e-12)
+	assert.Nil(t, must.V(ConvertUnit(nil, g, kg)))
+	assert.Equal(t, 1.5, must.V(ConvertUnit(1.5, nil, nil)), "no units")
+
+	_, err = ConvertUnit(1.0, g, standardUnit(t, "meter"))
+	assert.ErrorContains(t, err, string(seederrors.ValueUnit))
+	_, err = ConvertUnit(1.0, nil, g)
+	assert.ErrorContains(t, err, string(seederrors.ValueUnit))
+	_, err = ConvertUnit("1", g, kg)
+	assert.ErrorContains(t, err, string(seederrors.ValueType))
+}
+
+func TestUnitCovers(t *testing.T) {
+	g, kg := standardUnit(t, "gram"), standardUnit(t, "kilogram")
+	grams := IntegerSetting{Min: big.NewInt(0), Max: big.NewInt(5000), Unit: g}
+	assert.True(t, grams.Covers(IntegerSetting{Min: big.NewInt(0), Max: big.NewInt(5), Unit: kg}))
+	assert.False(t, grams.Covers(IntegerSetting{Min: big.NewInt(0), Max: big.NewInt(6), Unit: kg}), "range does not fit")
+	assert.False(t, IntegerSetting{Min: big.NewInt(0), Max: big.NewInt(5), Unit: kg}.Covers(grams), "grams are not whole kilograms")
+	assert.False(t, grams.Covers(IntegerSetting{Min: big.NewInt(0), Max: big.NewInt(5)}), "missing unit")
+
+	c, f := standardUnit(t, "celsius"), standardUnit(t, "fahrenheit")
+	celsius := RealSetting{Standard: Float64, MinFloat: new(float64), MaxFloat: ptr(100.0), Unit: c}
+	assert.True(t, celsius.Covers(RealSetting{Standard: Float64, MinFloat: ptr(32.0), MaxFloat: ptr(212.0), Unit: f}))
+	assert.False(t, celsius.Covers(RealSetting{Standard: Float64, MinFloat: ptr(0.0), MaxFloat: ptr(212.0), Unit: f}))
+	assert.False(t, celsius.Covers(RealSetting{Standard: Decimal64, Unit: f}), "converted decimals are not supported")
+
+	bad := queryUnitDomain(g)
+	bad.Objects.Values()[0].Fields.Values()[0].FieldTypeSetting = IntegerSetting{
+		Min: big.NewInt(0), Max: big.NewInt(1), Unit: &Unit{Thing: Thing{Name: "bad"}, Dimension: "mass", Factor: big.NewRat(-1, 1)},
+	}
+	assert.ErrorAs(t, bad.Validate(), &seederrors.DefinitionErrors{})
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func queryUnitDomain(u *Unit) *Domain {
+	return must.V(NewDomain(Thing{Name: "unit_test"}, &Object{
+		Thing: Thing{Name: "parcel"},
+		FieldGroup: FieldGroup{
+			Fields: must.V(NewFields(&Field{
+				Thing:            Thing{Name: "weight"},
+				FieldType:        Integer,
+				FieldTypeSetting: IntegerSetting{Min: big.NewInt(0), Max: big.NewInt(1000000), Unit: u},
+			})),
+		},
+	}))
+}
+
+func TestQueryConvertQuantities(t *testing.T) {
+	g, kg := standardUnit(t, "gram"), standardUnit(t, "kilogram")
+	d := queryUnitDomain(g)
+	require.NoError(t, d.Validate())
+	q := Query{
+		ObjectName: ObjectNamePath{Domain: "unit_test", Object: "parcel"},
+		Condition: Condition{Op: And, Children: []Condition{
+			{Op: Lt, FieldPaths: []Path{{"weight"}}, Literal: Quantity{Value: int64(2), Unit: kg}},
+			{Op: In, FieldPaths: []Path{{"weight"}}, Literal: []any{Quantity{Value: int64(1), Unit: kg}, int64(5)}},
+			{Op: Gt, Children: []Condition{{Op: PushUp, Literal: Quantity{Value: int64(3), Unit: kg}}}, FieldPaths: []Path{{"weight"}}},
+		}},
+	}
+	require.NoError(t, q.Validate(d))
+
+	data, err := json.Marshal(q)
+	require.NoError(t, err)
+	var decoded Query
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, q, decoded)
+
+	converted, err := q.ConvertQuantities(d)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2000), converted.Condition.Children[0].Literal)
+	assert.Equal(t, []any{int64(1000), int64(5)}, converted.Condition.Children[1].Literal)
+	assert.Equal(t, int64(3000), converted.Condition.Children[2].Children[0].Literal)
+	assert.Equal(t, Quantity{Value: int64(2), Unit: kg}, q.Condition.Children[0].Literal, "q is not changed")
+	require.NoError(t, converted.Validate(d))
+
+	q.Condition.Children[0].Literal = Quantity{Value: int64(2), Unit: standardUnit(t, "meter")}
+	assert.ErrorContains(t, q.Validate(d), string(seederrors.QueryLiteralType))
+	_, err = q.ConvertQuantities(d)
+	assert.ErrorContains(t, err, string(seederrors.ValueUnit))
+}
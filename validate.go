@@ -0,0 +1,274 @@
+package seed
+
+import (
+	"strconv"
+
+	"github.com/xiegeo/must"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// Validate checks the domain with ValidateDomain.
+func (d *Domain) Validate() error {
+	return ValidateDomain(d)
+}
+
+// ValidateDomain walks every object, field group, list item and combination in d to check
+// rules that can not be checked by dictionary naming rules alone, such as:
+//
+//   - Identities and Ranges only name fields that exist.
+//   - Ranges start and end on different, orderable fields of the same type.
+//   - References resolve to a real object and identity.
+//   - Field type settings match their field types and are valid.
+//
+// All violations found are returned together as seederrors.DefinitionErrors, or nil if none found.
+func ValidateDomain(d DomainGetter) error {
+	v := validator{domain: d}
+	must.NoError(d.GetObjects().RangeLogical(func(cn CodeName, ob ObjectGetter) error {
+		v.fieldGroup(ob, string(d.GetName()), string(cn))
+		return nil
+	}))
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+type validator struct {
+	domain DomainGetter
+	errs   seederrors.DefinitionErrors
+}
+
+func (v *validator) add(rule seederrors.DefinitionRule, value CodeName, path ...string) {
+	v.errs = append(v.errs, seederrors.NewDefinitionError(rule, value, path...))
+}
+
+// withPath returns a new path, so that path can be reused by the caller.
+func withPath(path []string, more ...string) []string {
+	out := make([]string, 0, len(path)+len(more))
+	out = append(out, path...)
+	return append(out, more...)
+}
+
+func (v *validator) fieldGroup(g FieldGroupGetter, path ...string) {
+	fields := g.GetFields()
+	if fields == nil {
+		v.add(seederrors.DefinitionFieldNotFound, "", withPath(path, "fields")...)
+		return
+	}
+	must.NoError(fields.RangeLogical(func(cn CodeName, f *Field) error {
+		v.field(f, withPath(path, string(cn))...)
+		return nil
+	}))
+	for i, id := range g.GetIdentities() {
+		idPath := withPath(path, "identities", strconv.Itoa(i))
+		if len(id.Fields)+len(id.Ranges) == 0 {
+			v.add(seederrors.DefinitionIdentityEmpty, id.Name, idPath...)
+		}
+		for _, name := range id.Fields {
+			if _, ok := fields.Get(name); !ok {
+				v.add(seederrors.DefinitionFieldNotFound, name, idPath...)
+			}
+		}
+		for j, r := range id.Ranges {
+			v.fieldRange(fields.Get, r, withPath(idPath, "ranges", strconv.Itoa(j))...)
+		}
+	}
+	for i, r := range g.GetRanges() {
+		v.fieldRange(fields.Get, r, withPath(path, "ranges", strconv.Itoa(i))...)
+	}
+}
+
+func (v *validator) fieldRange(get func(CodeName) (*Field, bool), r Range, path ...string) {
+	start, startFound := get(r.Start)
+	if !startFound {
+		v.add(seederrors.DefinitionFieldNotFound, r.Start, withPath(path, "start")...)
+	}
+	end, endFound := get(r.End)
+	if !endFound {
+		v.add(seederrors.DefinitionFieldNotFound, r.End, withPath(path, "end")...)
+	}
+	if !startFound || !endFound {
+		return
+	}
+	switch {
+	case r.Start == r.End:
+		v.add(seederrors.DefinitionRangeSame, r.Start, path...)
+	case start.FieldType != end.FieldType:
+		v.add(seederrors.DefinitionRangeType, CodeName(start.FieldType.String()+","+end.FieldType.String()), path...)
+	case !start.FieldType.IsOrderable():
+		v.add(seederrors.DefinitionRangeOrderable, CodeName(start.FieldType.String()), path...)
+	}
+}
+
+func (v *validator) field(f *Field, path ...string) {
+	if !f.FieldType.Valid() {
+		v.add(seederrors.DefinitionFieldType, CodeName(f.FieldType.String()), path...)
+		return
+	}
+	v.setting(f.FieldType, f.FieldTypeSetting, path...)
+	if f.Default != nil {
+		v.fieldDefault(f, withPath(path, "Default")...)
+	}
+}
+
+func (v *validator) fieldDefault(f *Field, path ...string) {
+	d := f.Default
+	switch {
+	case d.Generator == GenerateNow:
+		if f.FieldType != TimeStamp || d.Value != nil {
+			v.add(seederrors.DefinitionDefault, CodeName(d.Generator.String()), withPath(path, "Generator")...)
+		}
+	case d.Generator != GeneratorUnset:
+		v.add(seederrors.DefinitionDefault, CodeName(d.Generator.String()), withPath(path, "Generator")...)
+	case d.Value == nil:
+		v.add(seederrors.DefinitionDefault, "nil", withPath(path, "Value")...)
+	case f.IsI18n || !f.FieldType.IsOrderable() && f.FieldType != Enumeration:
+		v.add(seederrors.DefinitionDefault, CodeName(f.FieldType.String()), withPath(path, "Value")...)
+	case settingMatchFieldType(f.FieldType, f.FieldTypeSetting):
+		var c valueChecker
+		c.setting(f.FieldTypeSetting, derefValue(d.Value))
+		if err := c.result(); err != nil {
+			v.add(seederrors.DefinitionDefault, CodeName(err.Error()), withPath(path, "Value")...)
+		}
+	}
+}
+
+func (v *validator) setting(t FieldType, s FieldTypeSetting, path ...string) {
+	if !settingMatchFieldType(t, s) {
+		v.add(seederrors.DefinitionSettingType, CodeName(t.String()), path...)
+		return
+	}
+	invalid := func(value CodeName, option string) {
+		v.add(seederrors.DefinitionSetting, value, withPath(path, option)...)
+	}
+	switch vt := s.(type) {
+	case StringSetting:
+		if vt.MinCodePoints > vt.MaxCodePoints {
+			invalid("", "MaxCodePoints")
+		}
+	case BinarySetting:
+		if vt.MinBytes > vt.MaxBytes {
+			invalid("", "MaxBytes")
+		}
+	case TimeStampSetting:
+		if vt.Min.After(vt.Max) {
+			invalid("", "Max")
+		}
+		if vt.Scale <= 0 {
+			invalid(CodeName(vt.Scale.String()), "Scale")
+		}
+	case IntegerSetting:
+		if vt.Min == nil || vt.Max == nil {
+			invalid("nil", "Min")
+		} else if vt.Min.Cmp(vt.Max) > 0 {
+			invalid(CodeName(vt.Max.String()), "Max")
+		}
+		v.unit(vt.Unit, path...)
+	case RealSetting:
+		if !vt.Valid() {
+			invalid(CodeName(vt.Standard.String()), "Standard")
+		}
+		v.unit(vt.Unit, path...)
+	case ReferenceSetting:
+		v.reference(vt, path...)
+	case ListSetting:
+		if vt.MinLength > vt.MaxLength {
+			invalid("", "MaxLength")
+		}
+		if !vt.ItemType.Valid() {
+			v.add(seederrors.DefinitionFieldType, CodeName(vt.ItemType.String()), withPath(path, "item")...)
+			return
+		}
+		v.setting(vt.ItemType, vt.ItemTypeSetting, withPath(path, "item")...)
+	case CombinationSetting:
+		v.fieldGroup(&vt, path...)
+	case EnumerationSetting:
+		v.enumeration(vt, path...)
+	}
+}
+
+func (v *validator) unit(u *Unit, path ...string) {
+	if u != nil && u.Factor != nil && u.Factor.Sign() <= 0 {
+		v.add(seederrors.DefinitionSetting, CodeName(u.Factor.String()), withPath(path, "Unit", "Factor")...)
+	}
+}
+
+func (v *validator) enumeration(s EnumerationSetting, path ...string) {
+	if len(s.Values) == 0 {
+		v.add(seederrors.DefinitionValuesEmpty, "", withPath(path, "Values")...)
+	}
+	names := NewObjects0[EnumerationValue]()
+	for i, value := range s.Values {
+		if err := names.AddValue(value); err != nil {
+			v.add(seederrors.DefinitionValueName, value.Name, withPath(path, "Values", strconv.Itoa(i))...)
+		}
+	}
+}
+
+func (v *validator) reference(s ReferenceSetting, path ...string) {
+	target, ok := v.domain.GetObjects().Get(s.Object)
+	if !ok {
+		v.add(seederrors.DefinitionObjectNotFound, s.Object, withPath(path, "Object")...)
+		return
+	}
+	if _, ok = FindIdentity(target, s.Identity); !ok {
+		v.add(seederrors.DefinitionIdentityMissing, s.Identity, withPath(path, "Identity")...)
+	}
+	for i, promote := range s.PromotionMap {
+		if _, ok = target.GetFields().Get(promote.Target); !ok {
+			v.add(seederrors.DefinitionFieldNotFound, promote.Target, withPath(path, "PromotionMap", strconv.Itoa(i))...)
+		}
+	}
+}
+
+// FindIdentity finds an identity in g by name. If name is empty, the first identity is returned.
+// If no identity has this name, an identity of just the field by this name is returned.
+func FindIdentity(g FieldGroupGetter, name CodeName) (Identity, bool) {
+	ids := g.GetIdentities()
+	if name == "" {
+		if len(ids) == 0 {
+			return Identity{}, false
+		}
+		return ids[0], true
+	}
+	for _, id := range ids {
+		if id.Name == name {
+			return id, true
+		}
+	}
+	for _, id := range ids {
+		if len(id.Fields) == 1 && len(id.Ranges) == 0 && id.Fields[0] == name {
+			return id, true
+		}
+	}
+	return Identity{}, false
+}
+
+// settingMatchFieldType returns true if the setting s is the setting type used by field type t.
+func settingMatchFieldType(t FieldType, s FieldTypeSetting) bool {
+	var ok bool
+	switch t {
+	case String:
+		_, ok = s.(StringSetting)
+	case Binary:
+		_, ok = s.(BinarySetting)
+	case Boolean:
+		_, ok = s.(BooleanSetting)
+	case TimeStamp:
+		_, ok = s.(TimeStampSetting)
+	case Integer:
+		_, ok = s.(IntegerSetting)
+	case Real:
+		_, ok = s.(RealSetting)
+	case Reference:
+		_, ok = s.(ReferenceSetting)
+	case List:
+		_, ok = s.(ListSetting)
+	case Combination:
+		_, ok = s.(CombinationSetting)
+	case Enumeration:
+		_, ok = s.(EnumerationSetting)
+	}
+	return ok
+}
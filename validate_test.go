@@ -0,0 +1,129 @@
+package seed_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
+
+	. "github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/demo/testdomain"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+func TestValidateDomain(t *testing.T) {
+	require.NoError(t, testdomain.DomainLevel1().Validate())
+
+	bad := testdomain.ObjLevel0()
+	bad.Name = "bad"
+	bad.Identities = append(bad.Identities, Identity{
+		Fields: []CodeName{"not_a_field"},
+	}, Identity{})
+	bad.Ranges = []Range{
+		{Start: testdomain.Bool().Name, End: testdomain.Bool().Name},
+		{Start: testdomain.Bool().Name, End: testdomain.JSInteger().Name},
+		{Start: "no_start", End: testdomain.JSInteger().Name},
+	}
+	require.NoError(t, bad.Fields.AddValue(
+		&Field{
+			Thing:            Thing{Name: "wrong_setting"},
+			FieldType:        Integer,
+			FieldTypeSetting: StringSetting{},
+		},
+		&Field{
+			Thing:     Thing{Name: "min_over_max"},
+			FieldType: Integer,
+			FieldTypeSetting: IntegerSetting{
+				Min: big.NewInt(2),
+				Max: big.NewInt(1),
+			},
+		},
+		&Field{
+			Thing:            Thing{Name: "ref_missing"},
+			FieldType:        Reference,
+			FieldTypeSetting: ReferenceSetting{Object: "missing"},
+		},
+		&Field{
+			Thing:            Thing{Name: "ref_identity_missing"},
+			FieldType:        Reference,
+			FieldTypeSetting: ReferenceSetting{Object: "level_0", Identity: "missing"},
+		},
+		&Field{
+			Thing:            Thing{Name: "ref_by_field"},
+			FieldType:        Reference,
+			FieldTypeSetting: ReferenceSetting{Object: "level_0", Identity: testdomain.TextLineField().Name},
+		},
+		&Field{
+			Thing:     Thing{Name: "combination"},
+			FieldType: Combination,
+			FieldTypeSetting: CombinationSetting{
+				Fields:     must.V(NewFields(testdomain.Bool())),
+				Identities: []Identity{{Fields: []CodeName{"missing"}}},
+			},
+		},
+		&Field{
+			Thing:     Thing{Name: "list"},
+			FieldType: List,
+			FieldTypeSetting: ListSetting{
+				ItemType:        Real,
+				ItemTypeSetting: RealSetting{},
+			},
+		},
+		&Field{
+			Thing:            Thing{Name: "enum_empty"},
+			FieldType:        Enumeration,
+			FieldTypeSetting: EnumerationSetting{},
+		},
+		&Field{
+			Thing:     Thing{Name: "enum_repeated"},
+			FieldType: Enumeration,
+			FieldTypeSetting: EnumerationSetting{Values: []EnumerationValue{
+				{Thing: Thing{Name: "a"}}, {Thing: Thing{Name: "b"}}, {Thing: Thing{Name: "a"}},
+			}},
+		},
+		&Field{
+			Thing:            Thing{Name: "default_type"},
+			FieldType:        Integer,
+			FieldTypeSetting: Int64Setting(),
+			Default:          &Default{Value: "1"},
+		},
+		&Field{
+			Thing:            Thing{Name: "default_now"},
+			FieldType:        Boolean,
+			FieldTypeSetting: BooleanSetting{},
+			Default:          &Default{Generator: GenerateNow},
+		},
+	))
+	domain := must.V(NewDomain(Thing{Name: "test"}, testdomain.ObjLevel0(), bad))
+	err := domain.Validate()
+	var errs seederrors.DefinitionErrors
+	require.ErrorAs(t, err, &errs)
+	type ruleAt struct {
+		rule seederrors.DefinitionRule
+		path string
+	}
+	got := make([]ruleAt, len(errs))
+	for i, e := range errs {
+		got[i] = ruleAt{rule: e.Rule, path: strings.Join(e.Path, ".")}
+	}
+	assert.Equal(t, []ruleAt{
+		{seederrors.DefinitionSettingType, "test.bad.wrong_setting"},
+		{seederrors.DefinitionSetting, "test.bad.min_over_max.Max"},
+		{seederrors.DefinitionObjectNotFound, "test.bad.ref_missing.Object"},
+		{seederrors.DefinitionIdentityMissing, "test.bad.ref_identity_missing.Identity"},
+		{seederrors.DefinitionFieldNotFound, "test.bad.combination.identities.0"},
+		{seederrors.DefinitionSetting, "test.bad.list.item.Standard"},
+		{seederrors.DefinitionValuesEmpty, "test.bad.enum_empty.Values"},
+		{seederrors.DefinitionValueName, "test.bad.enum_repeated.Values.2"},
+		{seederrors.DefinitionDefault, "test.bad.default_type.Default.Value"},
+		{seederrors.DefinitionDefault, "test.bad.default_now.Default.Generator"},
+		{seederrors.DefinitionFieldNotFound, "test.bad.identities.1"},
+		{seederrors.DefinitionIdentityEmpty, "test.bad.identities.2"},
+		{seederrors.DefinitionRangeSame, "test.bad.ranges.0"},
+		{seederrors.DefinitionRangeType, "test.bad.ranges.1"},
+		{seederrors.DefinitionFieldNotFound, "test.bad.ranges.2.start"},
+	}, got)
+}
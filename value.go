@@ -0,0 +1,272 @@
+package seed
+
+import (
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// ObjectValue holds the values of an object or a combination field, keyed by field name.
+// Values are checked against field definitions when set with SetField.
+type ObjectValue struct {
+	Fields map[CodeName]FieldValue
+}
+
+// NewObjectValue returns an empty ObjectValue ready to use.
+func NewObjectValue() *ObjectValue {
+	return &ObjectValue{Fields: make(map[CodeName]FieldValue)}
+}
+
+// FieldValue holds a single value of FieldValueType, or nil for null.
+type FieldValue struct {
+	value any
+}
+
+// NewFieldValue wraps v, such as to build the items of a list value.
+func NewFieldValue[T FieldValueType](v T) FieldValue {
+	return FieldValue{value: v}
+}
+
+// Value returns the wrapped value.
+func (v FieldValue) Value() any {
+	return v.value
+}
+
+// ReferenceValue holds the values of the identity fields of the referenced object.
+type ReferenceValue struct {
+	ObjectValue
+}
+
+type SetFieldOption struct {
+	SkipCheck bool // if true, the value is set without checking it against the field.
+}
+
+type FieldValueType interface {
+	string | *string | I18n[string] | // String FieldType
+		[]byte | I18n[[]byte] | // Binary
+		bool | *bool | // Boolean
+		time.Time | *time.Time | // TimeStamp
+		int64 | *int64 | *big.Int | // Integer
+		float64 | *float64 | decimal.Decimal | *decimal.Decimal | // Real
+		ReferenceValue | // Reference
+		[]FieldValue | // List
+		ObjectValue | // Combination
+		CodeName | *CodeName // Enumeration
+}
+
+// SetField sets the value of field in ob. The value is checked by Field.CheckValue,
+// unless opt.SkipCheck is set.
+func SetField[T FieldValueType](ob *ObjectValue, field *Field, value T, opt *SetFieldOption) error {
+	fv := FieldValue{value: value}
+	if opt == nil || !opt.SkipCheck {
+		err := field.CheckValue(fv.plain())
+		if err != nil {
+			return err
+		}
+	}
+	if ob.Fields == nil {
+		ob.Fields = make(map[CodeName]FieldValue)
+	}
+	ob.Fields[field.Name] = fv
+	return nil
+}
+
+// GetField gets the value of field from ob as type T. Values can be converted between
+// pointers and values of the same type, and between integer types.
+func GetField[T FieldValueType](ob *ObjectValue, field *Field) (T, error) {
+	var vt T
+	v, ok := ob.Fields[field.Name]
+	if !ok {
+		return vt, seederrors.NewFieldNotFoundError(field.Name)
+	}
+	vt, ok = v.value.(T)
+	if ok { // fast pass
+		return vt, nil
+	}
+	vt, ok = convertValue[T](v.value)
+	if ok {
+		return vt, nil
+	}
+	return vt, seederrors.NewTargetValueTypeNotSupportedError(field.Name, v.value, vt)
+}
+
+// ConvertValue converts v to type T, with the conversions of GetField. Null values are converted
+// to zero values of nilable types. Strings can be converted to CodeName, and slices to []any.
+func ConvertValue[T any](v any) (T, error) {
+	vt, ok := convertValue[T](v)
+	if !ok {
+		return vt, seederrors.NewTargetValueTypeNotSupportedError("", v, vt)
+	}
+	return vt, nil
+}
+
+// MapValue gets the value of name from plain values, such as values returned by ObjectValue.Map,
+// and converts it by ConvertValue. A missing value is null.
+func MapValue[T any](m map[CodeName]any, name CodeName) (T, error) {
+	vt, ok := convertValue[T](m[name])
+	if !ok {
+		if _, found := m[name]; !found {
+			return vt, seederrors.NewValueRequiredError(name)
+		}
+		return vt, seederrors.NewTargetValueTypeNotSupportedError(name, m[name], vt)
+	}
+	return vt, nil
+}
+
+// ConvertList converts each item of a list value by convert. Null is converted to a nil slice.
+func ConvertList[T any](v any, convert func(any) (T, error)) ([]T, error) {
+	rv := reflect.ValueOf(derefValue(v))
+	if !rv.IsValid() {
+		return nil, nil
+	}
+	if rv.Kind() != reflect.Slice {
+		return nil, seederrors.NewTargetValueTypeNotSupportedError("", v, []T(nil))
+	}
+	out := make([]T, rv.Len())
+	for i := range out {
+		var err error
+		out[i], err = convert(rv.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+var _typeAnySlice = reflect.TypeOf([]any{})
+
+func convertValue[T any](v any) (T, bool) {
+	if vt, ok := v.(T); ok { // fast pass
+		return vt, true
+	}
+	var vt T
+	target := reflect.TypeOf(&vt).Elem()
+	if i, ok := toBigInt(derefValue(v)); ok {
+		switch any(vt).(type) {
+		case int64, *int64:
+			if !i.IsInt64() {
+				return vt, false
+			}
+			v = i.Int64()
+		case *big.Int:
+			return any(new(big.Int).Set(i)).(T), true //nolint:forcetypeassert // checked by type switch
+		}
+	}
+	rv := reflect.ValueOf(derefValue(v))
+	switch {
+	case !rv.IsValid():
+		switch target.Kind() { //nolint:exhaustive // other kinds are not nilable
+		case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+			return vt, true // null as nil
+		}
+		return vt, false
+	case rv.Type() == target,
+		rv.Kind() == reflect.String && target.Kind() == reflect.String:
+		return rv.Convert(target).Interface().(T), true //nolint:forcetypeassert // checked by type
+	case target.Kind() == reflect.Pointer && rv.Type() == target.Elem():
+		ptr := reflect.New(target.Elem())
+		ptr.Elem().Set(rv)
+		return ptr.Interface().(T), true //nolint:forcetypeassert // checked by type
+	case target == _typeAnySlice && rv.Kind() == reflect.Slice:
+		out := make([]any, rv.Len())
+		for i := range out {
+			out[i] = rv.Index(i).Interface()
+		}
+		return any(out).(T), true //nolint:forcetypeassert // checked by type
+	}
+	return vt, false
+}
+
+// Map returns the values as plain Go values, with nested values also converted:
+// combinations and references as map[CodeName]any, and lists as []any.
+// This is the form used by seedfake and accepted by persistence layers.
+func (ob *ObjectValue) Map() map[CodeName]any {
+	out := make(map[CodeName]any, len(ob.Fields))
+	for cn, v := range ob.Fields {
+		out[cn] = v.plain()
+	}
+	return out
+}
+
+func (v FieldValue) plain() any {
+	switch vt := v.value.(type) {
+	case ObjectValue:
+		return vt.Map()
+	case ReferenceValue:
+		return vt.Map()
+	case []FieldValue:
+		out := make([]any, len(vt))
+		for i, item := range vt {
+			out[i] = item.plain()
+		}
+		return out
+	}
+	return v.value
+}
+
+// ObjectValueFromMap wraps plain Go values, such as values read from a persistence layer,
+// after checking them against g with CheckValues.
+func ObjectValueFromMap[K ~string](g FieldGroupGetter, values map[K]any) (*ObjectValue, error) {
+	err := CheckValues(g, values)
+	if err != nil {
+		return nil, err
+	}
+	out := NewObjectValue()
+	for k, v := range values {
+		f, _ := g.GetFields().Get(CodeName(k))
+		out.Fields[CodeName(k)] = fieldValueFromPlain(f, v)
+	}
+	return out, nil
+}
+
+func fieldValueFromPlain(f *Field, v any) FieldValue {
+	switch vt := f.FieldTypeSetting.(type) {
+	case CombinationSetting, ReferenceSetting:
+		var m map[CodeName]any
+		switch mt := v.(type) {
+		case map[CodeName]any:
+			m = mt
+		case map[string]any:
+			m = toCodeNameMap(mt)
+		default:
+			return FieldValue{value: v}
+		}
+		ob := ObjectValue{Fields: make(map[CodeName]FieldValue, len(m))}
+		for cn, value := range m {
+			sub, ok := getField(vt, cn)
+			if ok {
+				ob.Fields[cn] = fieldValueFromPlain(sub, value)
+			} else {
+				ob.Fields[cn] = FieldValue{value: value}
+			}
+		}
+		if _, ok := vt.(ReferenceSetting); ok {
+			return FieldValue{value: ReferenceValue{ObjectValue: ob}}
+		}
+		return FieldValue{value: ob}
+	case ListSetting:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice {
+			return FieldValue{value: v}
+		}
+		item := vt.ItemField(f)
+		items := make([]FieldValue, rv.Len())
+		for i := range items {
+			items[i] = fieldValueFromPlain(item, rv.Index(i).Interface())
+		}
+		return FieldValue{value: items}
+	}
+	return FieldValue{value: v}
+}
+
+// getField returns the field definition of a nested value, references are not resolved.
+func getField(s FieldTypeSetting, cn CodeName) (*Field, bool) {
+	if g, ok := s.(CombinationSetting); ok {
+		return g.GetFields().Get(cn)
+	}
+	return nil, false
+}
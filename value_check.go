@@ -0,0 +1,314 @@
+package seed
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/xiegeo/seed/seederrors"
+)
+
+// CheckValue checks v against the field definition, and returns seederrors.ValueErrors listing
+// all rules broken, with paths starting from the field name.
+//
+// Values use the same Go types as seedfake and sqldb, pointers are dereferenced and nil is null.
+// List values are slices, combination values are map[CodeName]any or map[string]any,
+// and values of I18n fields are maps keyed by language.Tag.
+// Reference values are not checked, as they depend on the referenced object.
+func (f *Field) CheckValue(v any) error {
+	var c valueChecker
+	c.field(f, v, string(f.Name))
+	return c.result()
+}
+
+// CheckValues checks values keyed by field name against a field group, such as an object.
+// Unknown fields, missing fields that are not nullable and have no default,
+// and values in the wrong order for a range are reported in addition to the checks done by Field.CheckValue.
+func CheckValues[K ~string](g FieldGroupGetter, values map[K]any) error {
+	var c valueChecker
+	c.fieldGroup(g, toCodeNameMap(values))
+	return c.result()
+}
+
+type valueChecker struct {
+	errs  seederrors.ValueErrors
+	other error // errors that are not caused by the value, such as unsupported settings
+}
+
+func (c *valueChecker) add(rule seederrors.ValueRule, v any, path ...string) {
+	c.errs = append(c.errs, seederrors.NewValueError(rule, v, path...))
+}
+
+func (c *valueChecker) result() error {
+	if len(c.errs) == 0 {
+		return c.other
+	}
+	return seederrors.CombineErrors(c.errs, c.other)
+}
+
+func toCodeNameMap[K ~string](m map[K]any) map[CodeName]any {
+	out := make(map[CodeName]any, len(m))
+	for k, v := range m {
+		out[CodeName(k)] = v
+	}
+	return out
+}
+
+// derefValue removes pointers to values, nil pointers return nil.
+func derefValue(v any) any {
+	if _, ok := v.(*big.Int); ok {
+		return v
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	return rv.Interface()
+}
+
+func (c *valueChecker) field(f *Field, v any, path ...string) {
+	v = derefValue(v)
+	if v == nil {
+		if !f.Nullable {
+			c.add(seederrors.ValueRequired, v, path...)
+		}
+		return
+	}
+	if !f.IsI18n {
+		c.setting(f.FieldTypeSetting, v, path...)
+		return
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map {
+		c.add(seederrors.ValueType, v, path...)
+		return
+	}
+	iter := rv.MapRange()
+	for iter.Next() {
+		c.setting(f.FieldTypeSetting, derefValue(iter.Value().Interface()), withPath(path, fmt.Sprint(iter.Key().Interface()))...)
+	}
+}
+
+func (c *valueChecker) fieldGroup(g FieldGroupGetter, values map[CodeName]any, path ...string) {
+	unknown := make([]CodeName, 0)
+	for cn := range values {
+		if _, ok := g.GetFields().Get(cn); !ok {
+			unknown = append(unknown, cn)
+		}
+	}
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i] < unknown[j] })
+	for _, cn := range unknown {
+		c.add(seederrors.ValueFieldNotFound, values[cn], withPath(path, string(cn))...)
+	}
+	_ = g.GetFields().RangeLogical(func(cn CodeName, f *Field) error {
+		v, found := values[cn]
+		if !found && f.Default != nil {
+			return nil
+		}
+		c.field(f, v, withPath(path, string(cn))...)
+		return nil
+	})
+	_ = RangeRanges(g, func(r Range) error {
+		start, end := derefValue(values[r.Start]), derefValue(values[r.End])
+		if start == nil || end == nil {
+			return nil
+		}
+		cmp, err := CompareValues(start, end)
+		if err != nil {
+			return nil //nolint:nilerr // wrong types are reported by field checks
+		}
+		if cmp > 0 || cmp == 0 && !r.IncludeEndValue {
+			c.add(seederrors.ValueRangeOrder, end, withPath(path, string(rangeKey(r)))...)
+		}
+		return nil
+	})
+}
+
+func (c *valueChecker) setting(s FieldTypeSetting, v any, path ...string) {
+	switch vt := s.(type) {
+	case StringSetting:
+		c.string(vt, v, path)
+	case BinarySetting:
+		b, ok := v.([]byte)
+		if !ok {
+			c.add(seederrors.ValueType, v, path...)
+			return
+		}
+		c.length(int64(len(b)), vt.MinBytes, vt.MaxBytes, b, path)
+	case BooleanSetting:
+		if _, ok := v.(bool); !ok {
+			c.add(seederrors.ValueType, v, path...)
+		}
+	case TimeStampSetting:
+		c.timeStamp(vt, v, path)
+	case IntegerSetting:
+		c.integer(vt, v, path)
+	case RealSetting:
+		c.real(vt, v, path)
+	case ReferenceSetting:
+		// depends on the referenced object
+	case ListSetting:
+		c.list(vt, v, path)
+	case CombinationSetting:
+		switch m := v.(type) {
+		case map[CodeName]any:
+			c.fieldGroup(&vt, m, path...)
+		case map[string]any:
+			c.fieldGroup(&vt, toCodeNameMap(m), path...)
+		default:
+			c.add(seederrors.ValueType, v, path...)
+		}
+	case EnumerationSetting:
+		c.enumeration(vt, v, path)
+	default:
+		c.other = seederrors.CombineErrors(c.other,
+			seederrors.NewSystemError("CheckValue FieldTypeSetting type=%T not handled", s))
+	}
+}
+
+func (c *valueChecker) length(length, min, max int64, v any, path []string) {
+	if length < min {
+		c.add(seederrors.ValueTooShort, v, path...)
+	}
+	if length > max {
+		c.add(seederrors.ValueTooLong, v, path...)
+	}
+}
+
+func (c *valueChecker) string(s StringSetting, v any, path []string) {
+	str, ok := v.(string)
+	if !ok {
+		c.add(seederrors.ValueType, v, path...)
+		return
+	}
+	if s.IsSingleLine && strings.ContainsAny(str, "\r\n") {
+		c.add(seederrors.ValueMultiline, v, path...)
+	}
+	c.length(int64(utf8.RuneCountInString(str)), s.MinCodePoints, s.MaxCodePoints, v, path)
+}
+
+func (c *valueChecker) timeStamp(s TimeStampSetting, v any, path []string) {
+	t, ok := v.(time.Time)
+	if !ok {
+		c.add(seederrors.ValueType, v, path...)
+		return
+	}
+	if _, offset := t.Zone(); offset != 0 && !s.WithTimeZoneOffset {
+		c.add(seederrors.ValueTimeZone, v, path...)
+	}
+	if t.Before(s.Min) {
+		c.add(seederrors.ValueTooSmall, v, path...)
+	}
+	if t.After(s.Max) {
+		c.add(seederrors.ValueTooLarge, v, path...)
+	}
+	if s.Scale > 0 && !t.Equal(t.Truncate(s.Scale)) {
+		c.add(seederrors.ValuePrecision, v, path...)
+	}
+}
+
+func (c *valueChecker) integer(s IntegerSetting, v any, path []string) {
+	i, ok := toBigInt(v)
+	if !ok {
+		c.add(seederrors.ValueType, v, path...)
+		return
+	}
+	if s.Min != nil && i.Cmp(s.Min) < 0 {
+		c.add(seederrors.ValueTooSmall, v, path...)
+	}
+	if s.Max != nil && i.Cmp(s.Max) > 0 {
+		c.add(seederrors.ValueTooLarge, v, path...)
+	}
+}
+
+func (c *valueChecker) real(s RealSetting, v any, path []string) {
+	if !s.Valid() || s.Standard == CustomReal {
+		c.other = seederrors.CombineErrors(c.other,
+			seederrors.NewSystemError("CheckValue RealSetting.Standard %s not supported", s.Standard))
+		return
+	}
+	if s.Standard.IsDecimal() {
+		d, ok := v.(decimal.Decimal)
+		if !ok {
+			c.add(seederrors.ValueType, v, path...)
+		} else if !s.FitsDecimal(d) {
+			c.add(seederrors.ValuePrecision, v, path...)
+		}
+		return
+	}
+	f, ok := v.(float64)
+	if !ok {
+		c.add(seederrors.ValueType, v, path...)
+		return
+	}
+	if f < *s.MinFloat {
+		c.add(seederrors.ValueTooSmall, v, path...)
+	}
+	if f > *s.MaxFloat {
+		c.add(seederrors.ValueTooLarge, v, path...)
+	}
+}
+
+func (c *valueChecker) list(s ListSetting, v any, path []string) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		c.add(seederrors.ValueType, v, path...)
+		return
+	}
+	c.length(int64(rv.Len()), s.MinLength, s.MaxLength, v, path)
+	items := make([]any, rv.Len())
+	for i := range items {
+		items[i] = derefValue(rv.Index(i).Interface())
+		itemPath := withPath(path, strconv.Itoa(i))
+		if items[i] == nil {
+			c.add(seederrors.ValueRequired, items[i], itemPath...)
+			continue
+		}
+		c.setting(s.ItemTypeSetting, items[i], itemPath...)
+		if !s.IsUnique {
+			continue
+		}
+		for _, before := range items[:i] {
+			if equalValues(before, items[i]) {
+				c.add(seederrors.ValueRepeated, items[i], itemPath...)
+				break
+			}
+		}
+	}
+}
+
+func equalValues(a, b any) bool {
+	if cmp, err := CompareValues(a, b); err == nil {
+		return cmp == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func (c *valueChecker) enumeration(s EnumerationSetting, v any, path []string) {
+	var name CodeName
+	switch vt := v.(type) {
+	case CodeName:
+		name = vt
+	case string:
+		name = CodeName(vt)
+	default:
+		c.add(seederrors.ValueType, v, path...)
+		return
+	}
+	if !s.Has(name) {
+		c.add(seederrors.ValueNotAllowed, v, path...)
+	}
+}
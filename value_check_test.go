@@ -0,0 +1,102 @@
+package seed_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
+	"golang.org/x/text/language"
+
+	. "github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/demo/testdomain"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+type ruleAtValue struct {
+	rule seederrors.ValueRule
+	path string
+}
+
+func valueRules(t *testing.T, err error) []ruleAtValue {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var errs seederrors.ValueErrors
+	require.ErrorAs(t, err, &errs)
+	out := make([]ruleAtValue, len(errs))
+	for i, e := range errs {
+		out[i] = ruleAtValue{rule: e.Rule, path: strings.Join(e.Path, ".")}
+	}
+	return out
+}
+
+func TestFieldCheckValue(t *testing.T) {
+	text := testdomain.TextLineField()
+	for v, want := range map[any][]ruleAtValue{
+		"ok":              nil,
+		"line\nbreak":     {{seederrors.ValueMultiline, "text_10"}},
+		"more than 10 cp": {{seederrors.ValueTooLong, "text_10"}},
+		5:                 {{seederrors.ValueType, "text_10"}},
+	} {
+		assert.Equal(t, want, valueRules(t, text.CheckValue(v)), v)
+	}
+	assert.Equal(t, []ruleAtValue{{seederrors.ValueRequired, "text_10"}}, valueRules(t, text.CheckValue(nil)))
+	assert.NoError(t, text.CheckValue(valuePointer("pointer")))
+	text.Nullable = true
+	assert.NoError(t, text.CheckValue((*string)(nil)))
+
+	integer := testdomain.JSInteger()
+	assert.NoError(t, integer.CheckValue(int64(1)))
+	assert.NoError(t, integer.CheckValue(big.NewInt(-1)))
+	assert.Equal(t, []ruleAtValue{{seederrors.ValueTooLarge, "integer_js"}},
+		valueRules(t, integer.CheckValue(new(big.Int).Lsh(big.NewInt(1), 60))))
+
+	date := testdomain.DateTimeSec()
+	assert.NoError(t, date.CheckValue(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, []ruleAtValue{{seederrors.ValueTimeZone, "datetime_sec_9999"}, {seederrors.ValuePrecision, "datetime_sec_9999"}},
+		valueRules(t, date.CheckValue(time.Date(2000, 1, 1, 0, 0, 0, 1, time.FixedZone("", 3600)))))
+
+	list := testdomain.ListOf(testdomain.TextLineField(), ListSetting{MaxLength: 2, IsUnique: true})
+	assert.NoError(t, list.CheckValue([]string{"a", "b"}))
+	assert.Equal(t, []ruleAtValue{{seederrors.ValueTooLong, "list_text_10"}, {seederrors.ValueRepeated, "list_text_10.2"}},
+		valueRules(t, list.CheckValue([]any{"a", "b", "a"})))
+
+	i18n := testdomain.TextLineField()
+	i18n.IsI18n = true
+	assert.Equal(t, []ruleAtValue{{seederrors.ValueTooLong, "text_10.zh"}},
+		valueRules(t, i18n.CheckValue(I18n[string]{language.Chinese: "这是一个超过十个字的句子"})))
+
+	status := testdomain.Enumeration()
+	assert.NoError(t, status.CheckValue("draft"))
+	assert.Equal(t, []ruleAtValue{{seederrors.ValueNotAllowed, "status"}}, valueRules(t, status.CheckValue(CodeName("deleted"))))
+}
+
+func TestCheckValues(t *testing.T) {
+	start := testdomain.DateTimeSec()
+	end := testdomain.DateTimeSec()
+	end.Name = "end"
+	count := testdomain.JSInteger()
+	count.Default = &Default{Value: int64(0)}
+	ob := &Object{
+		Thing: Thing{Name: "event"},
+		FieldGroup: FieldGroup{
+			Fields: must.V(NewFields(start, end, count)),
+			Ranges: []Range{{Start: start.Name, End: end.Name}},
+		},
+	}
+	t0 := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+	assert.NoError(t, CheckValues(ob, map[string]any{"datetime_sec_9999": t0, "end": t1}))
+	assert.Equal(t, []ruleAtValue{
+		{seederrors.ValueFieldNotFound, "unknown"},
+		{seederrors.ValueRequired, "end"},
+	}, valueRules(t, CheckValues(ob, map[CodeName]any{"datetime_sec_9999": t0, "unknown": 1})))
+	assert.Equal(t, []ruleAtValue{
+		{seederrors.ValueRangeOrder, "(datetime_sec_9999..end)"},
+	}, valueRules(t, CheckValues(ob, map[CodeName]any{"datetime_sec_9999": t1, "end": t1})))
+}
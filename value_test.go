@@ -0,0 +1,59 @@
+package seed_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xiegeo/must"
+
+	. "github.com/xiegeo/seed"
+	"github.com/xiegeo/seed/demo/testdomain"
+	"github.com/xiegeo/seed/seederrors"
+)
+
+func TestObjectValue(t *testing.T) {
+	text := testdomain.TextLineField()
+	integer := testdomain.JSInteger()
+	list := testdomain.ListOf(testdomain.Bool(), ListSetting{MaxLength: 2})
+	ob := NewObjectValue()
+
+	require.NoError(t, SetField(ob, text, "hello", nil))
+	var errs seederrors.ValueErrors
+	assert.ErrorAs(t, SetField(ob, text, "more than 10 code points", nil), &errs)
+	assert.Equal(t, "hello", must.V(GetField[string](ob, text)), "failed set does not change value")
+	assert.Equal(t, "hello", *must.V(GetField[*string](ob, text)))
+	require.NoError(t, SetField(ob, text, "more than 10 code points", &SetFieldOption{SkipCheck: true}))
+
+	require.NoError(t, SetField(ob, integer, int64(42), nil))
+	assert.Equal(t, big.NewInt(42), must.V(GetField[*big.Int](ob, integer)))
+	_, err := GetField[string](ob, integer)
+	assert.ErrorAs(t, err, &seederrors.TargetValueTypeNotSupportedError{})
+	_, err = GetField[string](ob, testdomain.Bool())
+	assert.ErrorAs(t, err, &seederrors.FieldNotFoundError{})
+
+	items := []FieldValue{NewFieldValue(true), NewFieldValue(false)}
+	require.NoError(t, SetField(ob, list, items, nil))
+	assert.ErrorAs(t, SetField(ob, list, append(items, NewFieldValue(true)), nil), &errs)
+	assert.Equal(t, map[CodeName]any{
+		text.Name:    "more than 10 code points",
+		integer.Name: int64(42),
+		list.Name:    []any{true, false},
+	}, ob.Map())
+}
+
+func TestObjectValueFromMap(t *testing.T) {
+	list := testdomain.ListOf(testdomain.Bool(), ListSetting{MaxLength: 2})
+	g := &FieldGroup{Fields: must.V(NewFields(testdomain.TextLineField(), list))}
+	values := map[string]any{"text_10": "hi", "list_bool": []bool{true}}
+	ob, err := ObjectValueFromMap(g, values)
+	require.NoError(t, err)
+	assert.Equal(t, "hi", must.V(GetField[string](ob, testdomain.TextLineField())))
+	assert.Equal(t, []FieldValue{NewFieldValue(true)}, must.V(GetField[[]FieldValue](ob, list)))
+	assert.Equal(t, map[CodeName]any{"text_10": "hi", "list_bool": []any{true}}, ob.Map())
+
+	_, err = ObjectValueFromMap(g, map[string]any{"text_10": 1})
+	var errs seederrors.ValueErrors
+	assert.ErrorAs(t, err, &errs)
+}